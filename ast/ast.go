@@ -1,8 +1,8 @@
 package ast
 
 import (
-	"fmt"
 	"go/ast"
+	"strings"
 
 	"github.com/unmango/go-make/token"
 )
@@ -39,7 +39,8 @@ type IfDir interface {
 type File struct {
 	FileStart, FileEnd token.Pos
 
-	Contents []Obj // all file content
+	Contents []Obj       // all file content
+	Tabs     []token.Pos // positions of tabs indenting lines other than recipe lines
 }
 
 // Pos implements Node
@@ -60,6 +61,24 @@ func (f *File) End() token.Pos {
 	}
 }
 
+// A BadObj node is a placeholder for an object containing syntax errors
+// for which a correct object node cannot be created.
+type BadObj struct {
+	From, To token.Pos // position range of bad object
+}
+
+func (*BadObj) objNode() {}
+
+// Pos implements Node
+func (o *BadObj) Pos() token.Pos {
+	return o.From
+}
+
+// End implements Node
+func (o *BadObj) End() token.Pos {
+	return o.To
+}
+
 // A CommentGroup represents a sequence of comments with no other tokens and no empty lines between.
 type CommentGroup struct {
 	List []*Comment
@@ -94,22 +113,33 @@ func (c *Comment) End() token.Pos {
 }
 
 // A Rule represents the Recipes and PreRequisites required to build Targets. [Rule Syntax]
+// A [Static Pattern] rule additionally has a target-pattern between Targets and PreReqs.
 //
 // [Rule Syntax]: https://www.gnu.org/software/make/manual/html_node/Rule-Syntax.html
+// [Static Pattern]: https://www.gnu.org/software/make/manual/html_node/Static-Usage.html
 type Rule struct {
-	Targets      []Expr    // rule targets
-	Colon        token.Pos // position of ':' separating targets and prerequisites
-	PreReqs      []Expr    // rule pre-requisites
-	Pipe         token.Pos // position of '|' separating normal and order-only prerequisites
-	OrderPreReqs []Expr    // order-only pre-requisites
-	Recipes      []*Recipe // rule recipe lines
+	Doc          *CommentGroup // associated documentation; or nil
+	Targets      []Expr        // rule targets
+	Tok          token.Token   // COLON, DOUBLE_COLON for a double-colon rule, or AND_COLON for grouped targets
+	Colon        token.Pos     // position of Tok separating targets and prerequisites
+	Pattern      []Expr        // target-pattern of a static pattern rule, if it exists
+	PatternColon token.Pos     // position of ':' separating the target-pattern and prerequisites
+	PreReqs      []Expr        // rule pre-requisites
+	Pipe         token.Pos     // position of '|' separating normal and order-only prerequisites
+	OrderPreReqs []Expr        // order-only pre-requisites
+	Comment      *Comment      // trailing line comment, or nil
+	Recipes      []Obj         // recipe lines, conditional directives containing recipe lines, and comment groups between them
 }
 
 func (*Rule) objNode() {}
 
 // Pos implements Node
 func (r *Rule) Pos() token.Pos {
-	return r.Targets[0].Pos()
+	if len(r.Targets) > 0 {
+		return r.Targets[0].Pos()
+	} else {
+		return r.Colon
+	}
 }
 
 // End implements Node
@@ -123,8 +153,40 @@ func (r *Rule) End() token.Pos {
 	if n := len(r.PreReqs); n > 0 {
 		return r.PreReqs[n-1].End()
 	}
+	if r.PatternColon.IsValid() {
+		return r.PatternColon + 1
+	}
+
+	if r.Tok == token.DOUBLE_COLON || r.Tok == token.AND_COLON {
+		return r.Colon + 2 // pos + len("::") or len("&:")
+	} else {
+		return r.Colon + 1 // pos + len(":")
+	}
+}
+
+// Grouped reports whether the rule's targets are grouped targets, i.e. `foo bar &: baz`. [Multiple Targets]
+//
+// [Multiple Targets]: https://www.gnu.org/software/make/manual/html_node/Multiple-Targets.html
+func (r *Rule) Grouped() bool {
+	return r.Tok == token.AND_COLON
+}
+
+// A BadExpr node is a placeholder for an expression containing syntax errors
+// for which a correct expression node cannot be created.
+type BadExpr struct {
+	From, To token.Pos // position range of bad expression
+}
+
+func (*BadExpr) exprNode() {}
+
+// Pos implements Node
+func (x *BadExpr) Pos() token.Pos {
+	return x.From
+}
 
-	return r.Colon + 1
+// End implements Node
+func (x *BadExpr) End() token.Pos {
+	return x.To
 }
 
 // Text represents a string of text that has no special meaning to make.
@@ -154,7 +216,7 @@ func (l *Text) String() string {
 type QuotedExpr struct {
 	Quote token.Token // ' or "
 	Open  token.Pos   // position of the opening quote
-	Value Expr        // position of the inner expression
+	Value []Expr      // quoted expressions, which may be empty
 	Close token.Pos   // position of the closing quote
 }
 
@@ -172,16 +234,24 @@ func (l *QuotedExpr) End() token.Pos {
 
 // String returns the quoted expression
 func (l *QuotedExpr) String() string {
-	quote := l.Quote.String()
-	return fmt.Sprint(quote, l.Value, quote)
+	b := &strings.Builder{}
+	b.WriteString(l.Quote.String())
+	pos := writeList(b, l.Open+1, l.Value)
+	writeGap(b, pos, l.Close)
+	b.WriteString(l.Quote.String())
+
+	return b.String()
 }
 
-// VarRef represents a variable reference.
+// VarRef represents a variable reference. [Reference]
+//
+// [Reference]: https://www.gnu.org/software/make/manual/html_node/Reference.html
 type VarRef struct {
-	Dollar token.Pos   // position of '$'
-	Open   token.Token // opening token, '(', '{', or ILLEGAL if len(Name) == 1
-	Name   string      // variable identifier
-	Close  token.Token // closing token, ')', '}', or ILLEGAL if len(Name) == 1
+	Dollar   token.Pos   // position of '$'
+	Open     token.Token // opening token, '(', '{', or ILLEGAL for a single character name
+	Name     []Expr      // variable name, which may contain references, i.e. $($(ARCH)_CFLAGS)
+	Close    token.Token // closing token, ')', '}', or ILLEGAL for a single character name
+	ClosePos token.Pos   // position of Close, if it exists
 }
 
 func (*VarRef) exprNode() {}
@@ -193,30 +263,185 @@ func (v *VarRef) Pos() token.Pos {
 
 // End implements Node
 func (v *VarRef) End() token.Pos {
-	if n := len(v.Name); n == 1 {
-		return v.Dollar + 1
+	if v.ClosePos.IsValid() {
+		return v.ClosePos + 1 // pos + len(')')
+	} else if n := len(v.Name); n > 0 && v.Open == token.ILLEGAL {
+		return v.Name[n-1].End()
+	} else if n > 0 {
+		return v.Name[n-1].End() + 1 // pos + len(')')
+	} else if v.Open == token.ILLEGAL {
+		return v.Dollar + 1 // pos + len('$')
 	} else {
-		// '$' + '{' + len(v.Name) + '}'
-		return token.Pos(int(v.Dollar) + 2 + len(v.Name))
+		return v.Dollar + 3 // pos + len('$()')
 	}
 }
 
 // String implements fmt.Stringer
 func (v *VarRef) String() string {
-	if len(v.Name) == 1 {
-		return "$" + v.Name
+	b := &strings.Builder{}
+	if v.Open == token.ILLEGAL {
+		b.WriteString("$")
+		writeList(b, token.NoPos, v.Name)
 	} else {
-		return fmt.Sprint("$", v.Open, v.Name, v.Close)
+		b.WriteString("$" + v.Open.String())
+		pos := writeList(b, v.Dollar+2, v.Name)
+		writeGap(b, pos, v.ClosePos)
+		b.WriteString(v.Close.String())
 	}
+
+	return b.String()
+}
+
+// SubstRef represents a substitution reference, i.e. $(VAR:.c=.o) or $(VAR:%.c=%.o). [Substitution Refs]
+//
+// [Substitution Refs]: https://www.gnu.org/software/make/manual/html_node/Substitution-Refs.html
+type SubstRef struct {
+	Dollar   token.Pos   // position of '$'
+	Open     token.Token // opening token, '(' or '{'
+	Name     []Expr      // variable name
+	Colon    token.Pos   // position of ':'
+	From     []Expr      // suffix or pattern to replace
+	Assign   token.Pos   // position of '='
+	To       []Expr      // replacement suffix or pattern
+	Close    token.Token // closing token, ')' or '}'
+	ClosePos token.Pos   // position of Close
+}
+
+func (*SubstRef) exprNode() {}
+
+// Pos implements Node
+func (r *SubstRef) Pos() token.Pos {
+	return r.Dollar
+}
+
+// End implements Node
+func (r *SubstRef) End() token.Pos {
+	return r.ClosePos + 1 // pos + len(')')
+}
+
+// FuncCall represents a call to a built-in function. [Functions]
+//
+// [Functions]: https://www.gnu.org/software/make/manual/html_node/Functions.html
+type FuncCall struct {
+	Dollar   token.Pos   // position of '$'
+	Open     token.Token // opening token, '(' or '{'
+	Name     token.Token // built-in function, i.e. PATSUBST
+	NamePos  token.Pos   // position of Name
+	Args     [][]Expr    // comma separated arguments; an argument may be empty
+	Commas   []token.Pos // positions of ',' separating Args
+	Close    token.Token // closing token, ')' or '}'
+	ClosePos token.Pos   // position of Close
+}
+
+func (*FuncCall) exprNode() {}
+
+// Pos implements Node
+func (c *FuncCall) Pos() token.Pos {
+	return c.Dollar
+}
+
+// End implements Node
+func (c *FuncCall) End() token.Pos {
+	return c.ClosePos + 1 // pos + len(')')
+}
+
+// String implements fmt.Stringer
+func (r *SubstRef) String() string {
+	b := &strings.Builder{}
+	b.WriteString("$" + r.Open.String())
+	pos := writeList(b, r.Dollar+2, r.Name)
+	writeGap(b, pos, r.Colon)
+	b.WriteString(":")
+	pos = writeList(b, r.Colon+1, r.From)
+	writeGap(b, pos, r.Assign)
+	b.WriteString("=")
+	pos = writeList(b, r.Assign+1, r.To)
+	writeGap(b, pos, r.ClosePos)
+	b.WriteString(r.Close.String())
+
+	return b.String()
+}
+
+// String implements fmt.Stringer
+func (c *FuncCall) String() string {
+	b := &strings.Builder{}
+	b.WriteString("$" + c.Open.String() + c.Name.String())
+	pos := c.NamePos + token.Pos(len(c.Name.String()))
+	for i, arg := range c.Args {
+		if i > 0 && i <= len(c.Commas) {
+			writeGap(b, pos, c.Commas[i-1])
+			b.WriteString(",")
+			pos = c.Commas[i-1] + 1
+		}
+		pos = writeList(b, pos, arg)
+	}
+	writeGap(b, pos, c.ClosePos)
+	b.WriteString(c.Close.String())
+
+	return b.String()
+}
+
+// A Continuation represents a backslash-newline joining two lines.
+type Continuation struct {
+	Backslash token.Pos // position of '\'
+	Text      string    // "\\\n" followed by the indentation of the next line
+}
+
+func (*Continuation) exprNode() {}
+
+// Pos implements Node
+func (c *Continuation) Pos() token.Pos {
+	return c.Backslash
+}
+
+// End implements Node
+func (c *Continuation) End() token.Pos {
+	return token.Pos(int(c.Backslash) + len(c.Text))
+}
+
+// String returns the continuation text
+func (c *Continuation) String() string {
+	return c.Text
+}
+
+// An Escape represents an escaped dollar sign, i.e. $$.
+type Escape struct {
+	Dollar token.Pos // position of the first '$'
+}
+
+func (*Escape) exprNode() {}
+
+// Pos implements Node
+func (e *Escape) Pos() token.Pos {
+	return e.Dollar
+}
+
+// End implements Node
+func (e *Escape) End() token.Pos {
+	return e.Dollar + 2 // pos + len('$$')
+}
+
+// String implements fmt.Stringer
+func (e *Escape) String() string {
+	return "$$"
 }
 
 // A Recipe represents a line of text to be passed to the shell to build a Target.
+// When parsed in the ParseRecipes mode, Exprs holds the command text as expressions
+// and is printed in place of Text. A Recipe is an Obj so that it may appear in the
+// branches of a conditional directive within a rule's recipes.
 type Recipe struct {
-	Text                  // recipe text excluding '\n'
-	Prefix    token.Token // TAB, SEMI, or .RECIPEPREFIX
-	PrefixPos token.Pos   // position of Tok
+	Text                     // command text excluding modifiers and '\n'
+	Exprs        []Expr      // command text as expressions, or nil
+	Prefix       token.Token // TAB, SEMI, or TEXT for a custom .RECIPEPREFIX
+	PrefixPos    token.Pos   // position of Prefix
+	PrefixLit    string      // .RECIPEPREFIX character when Prefix is TEXT
+	Modifiers    string      // leading '@', '-' and '+' characters, including whitespace between them
+	ModifiersPos token.Pos   // position of Modifiers
 }
 
+func (*Recipe) objNode() {}
+
 // Pos implements Node
 func (r *Recipe) Pos() token.Pos {
 	return r.PrefixPos
@@ -224,22 +449,50 @@ func (r *Recipe) Pos() token.Pos {
 
 // End implements Node
 func (r *Recipe) End() token.Pos {
-	return token.Pos(int(r.PrefixPos) + len(r.Value))
+	return token.Pos(int(r.PrefixPos) + len(r.Modifiers) + len(r.Value))
+}
+
+// Silent reports whether the recipe has the '@' modifier. [Echoing]
+//
+// [Echoing]: https://www.gnu.org/software/make/manual/html_node/Echoing.html
+func (r *Recipe) Silent() bool {
+	return strings.ContainsRune(r.Modifiers, '@')
+}
+
+// IgnoreErrors reports whether the recipe has the '-' modifier. [Errors]
+//
+// [Errors]: https://www.gnu.org/software/make/manual/html_node/Errors.html
+func (r *Recipe) IgnoreErrors() bool {
+	return strings.ContainsRune(r.Modifiers, '-')
+}
+
+// Always reports whether the recipe has the '+' modifier. [Instead of Execution]
+//
+// [Instead of Execution]: https://www.gnu.org/software/make/manual/html_node/Instead-of-Execution.html
+func (r *Recipe) Always() bool {
+	return strings.ContainsRune(r.Modifiers, '+')
 }
 
 // An Variable represents a make variable.
 type Variable struct {
-	Name  Expr        // left-hand side of the assignment
-	Op    token.Token // =, :=, ::=, :::=, !=, ?=
-	OpPos token.Pos   // position of Op
-	Value []Expr      // right-hand side of the assignment
+	Doc       *CommentGroup // associated documentation; or nil
+	Modifiers []*Modifier   // override, export, unexport, or private modifiers
+	Name      Expr          // left-hand side of the assignment
+	Op        token.Token   // =, :=, ::=, :::=, !=, ?=, +=
+	OpPos     token.Pos     // position of Op
+	Value     []Expr        // right-hand side of the assignment
+	Comment   *Comment      // trailing line comment, or nil
 }
 
 func (*Variable) objNode() {}
 
 // Pos implements Node
 func (s *Variable) Pos() token.Pos {
-	return s.Name.Pos()
+	if len(s.Modifiers) > 0 {
+		return s.Modifiers[0].Pos()
+	} else {
+		return s.Name.Pos()
+	}
 }
 
 // End implements Node
@@ -251,12 +504,51 @@ func (s *Variable) End() token.Pos {
 	}
 }
 
+// A Modifier represents a keyword modifying a variable assignment, i.e. `override`.
+type Modifier struct {
+	Tok    token.Token // OVERRIDE, EXPORT, UNEXPORT, or PRIVATE
+	TokPos token.Pos   // position of Tok
+}
+
+// Pos implements Node
+func (m *Modifier) Pos() token.Pos {
+	return m.TokPos
+}
+
+// End implements Node
+func (m *Modifier) End() token.Pos {
+	return token.Pos(int(m.TokPos) + len(m.Tok.String()))
+}
+
+// TargetVariable represents a target-specific or pattern-specific variable assignment. [Target-specific]
+//
+// [Target-specific]: https://www.gnu.org/software/make/manual/html_node/Target_002dspecific.html
+type TargetVariable struct {
+	Targets  []Expr      // targets or patterns the assignment applies to
+	Tok      token.Token // COLON, DOUBLE_COLON, or AND_COLON
+	Colon    token.Pos   // position of Tok separating targets and the assignment
+	Variable *Variable   // variable assignment, including its modifiers
+}
+
+func (*TargetVariable) objNode() {}
+
+// Pos implements Node
+func (v *TargetVariable) Pos() token.Pos {
+	return v.Targets[0].Pos()
+}
+
+// End implements Node
+func (v *TargetVariable) End() token.Pos {
+	return v.Variable.End()
+}
+
 // IfBlock represents a conditional directive and its parts.
 type IfBlock struct {
-	Directive IfDir        // conditional directive
-	Text      []Obj        // text-if-true
-	Else      []*ElseBlock // else directive blocks
-	Endif     token.Pos    // position of ENDIF
+	Directive    IfDir        // conditional directive
+	Text         []Obj        // text-if-true
+	Else         []*ElseBlock // else directive blocks
+	Endif        token.Pos    // position of ENDIF
+	EndifComment *Comment     // trailing line comment after ENDIF, or nil
 }
 
 func (*IfBlock) objNode() {}
@@ -276,6 +568,7 @@ func (b *IfBlock) End() token.Pos {
 type ElseBlock struct {
 	Else      token.Pos // position of ELSE
 	Condition IfDir     // condition, if it exists; nil otherwise
+	Comment   *Comment  // trailing line comment when there is no condition, or nil
 	Text      []Obj     // text-if-true when a condition exists; text-if-false otherwise
 }
 
@@ -297,13 +590,14 @@ func (b *ElseBlock) End() token.Pos {
 
 // IfeqDir represents a conditional directive block using `ifeq` or `ifneq`.
 type IfeqDir struct {
-	Tok    token.Token // IFEQ or IFNEQ
-	TokPos token.Pos   // position of Tok
-	Open   token.Pos   // position of '(', if it exists
-	Arg1   Expr        // first argument in the condition
-	Comma  token.Pos   // position of ',', if it exists
-	Arg2   Expr        // second argument in the condition
-	Close  token.Pos   // position of ')', if it exists
+	Tok     token.Token // IFEQ or IFNEQ
+	TokPos  token.Pos   // position of Tok
+	Open    token.Pos   // position of '(', if it exists
+	Arg1    []Expr      // first argument in the condition, which may be empty
+	Comma   token.Pos   // position of ',', if it exists
+	Arg2    []Expr      // second argument in the condition, which may be empty
+	Close   token.Pos   // position of ')', if it exists
+	Comment *Comment    // trailing line comment, or nil
 }
 
 func (*IfeqDir) ifDirNode() {}
@@ -317,8 +611,10 @@ func (d *IfeqDir) Pos() token.Pos {
 func (d *IfeqDir) End() token.Pos {
 	if d.Close.IsValid() {
 		return d.Close + 1 // pos + len(')')
+	} else if n := len(d.Arg2); n > 0 {
+		return d.Arg2[n-1].End()
 	} else {
-		return d.Arg2.End()
+		return d.TokPos + token.Pos(len(d.Tok.String()))
 	}
 }
 
@@ -327,6 +623,7 @@ type IfdefDir struct {
 	Tok     token.Token // IFDEF or IFNDEF
 	TokPos  token.Pos   // position of Tok
 	VarName Expr        // variable-name
+	Comment *Comment    // trailing line comment, or nil
 }
 
 func (*IfdefDir) ifDirNode() {}
@@ -340,3 +637,179 @@ func (d *IfdefDir) Pos() token.Pos {
 func (d *IfdefDir) End() token.Pos {
 	return d.VarName.End()
 }
+
+// DefineDir represents a multi-line variable definition using `define` and `endef`. [Multi-Line]
+//
+// [Multi-Line]: https://www.gnu.org/software/make/manual/html_node/Multi_002dLine.html
+type DefineDir struct {
+	Modifiers []*Modifier // override, export, unexport, or private modifiers
+	Define    token.Pos   // position of DEFINE
+	Name      Expr        // variable name
+	Op        token.Token // assignment operator, or ILLEGAL if omitted
+	OpPos     token.Pos   // position of Op, if it exists
+	Body      []*Text     // raw lines of the variable value, excluding '\n'
+	Endef     token.Pos   // position of ENDEF
+	Comment   *Comment    // trailing line comment after ENDEF, or nil
+}
+
+func (*DefineDir) objNode() {}
+func (*DefineDir) dirNode() {}
+
+// Pos implements Node
+func (d *DefineDir) Pos() token.Pos {
+	if len(d.Modifiers) > 0 {
+		return d.Modifiers[0].Pos()
+	} else {
+		return d.Define
+	}
+}
+
+// End implements Node
+func (d *DefineDir) End() token.Pos {
+	return d.Endef + 5 // pos + len("endef")
+}
+
+// IncludeDir represents an `include`, `-include`, or `sinclude` directive. [Include]
+//
+// [Include]: https://www.gnu.org/software/make/manual/html_node/Include.html
+type IncludeDir struct {
+	Tok     token.Token // INCLUDE, DASH_INCLUDE, or SINCLUDE
+	TokPos  token.Pos   // position of Tok
+	Files   []Expr      // file name expressions
+	Comment *Comment    // trailing line comment, or nil
+}
+
+func (*IncludeDir) objNode() {}
+func (*IncludeDir) dirNode() {}
+
+// Pos implements Node
+func (d *IncludeDir) Pos() token.Pos {
+	return d.TokPos
+}
+
+// End implements Node
+func (d *IncludeDir) End() token.Pos {
+	if n := len(d.Files); n > 0 {
+		return d.Files[n-1].End()
+	} else {
+		return token.Pos(int(d.TokPos) + len(d.Tok.String()))
+	}
+}
+
+// ExportDir represents an `export` directive without an assignment. [Variables/Recursion]
+// An ExportDir with no Names exports all variables by default.
+//
+// [Variables/Recursion]: https://www.gnu.org/software/make/manual/html_node/Variables_002fRecursion.html
+type ExportDir struct {
+	Export  token.Pos // position of EXPORT
+	Names   []Expr    // variable names
+	Comment *Comment  // trailing line comment, or nil
+}
+
+func (*ExportDir) objNode() {}
+func (*ExportDir) dirNode() {}
+
+// Pos implements Node
+func (d *ExportDir) Pos() token.Pos {
+	return d.Export
+}
+
+// End implements Node
+func (d *ExportDir) End() token.Pos {
+	if n := len(d.Names); n > 0 {
+		return d.Names[n-1].End()
+	} else {
+		return d.Export + 6 // pos + len("export")
+	}
+}
+
+// UnexportDir represents an `unexport` directive without an assignment. [Variables/Recursion]
+// An UnexportDir with no Names unexports all variables by default.
+//
+// [Variables/Recursion]: https://www.gnu.org/software/make/manual/html_node/Variables_002fRecursion.html
+type UnexportDir struct {
+	Unexport token.Pos // position of UNEXPORT
+	Names    []Expr    // variable names
+	Comment  *Comment  // trailing line comment, or nil
+}
+
+func (*UnexportDir) objNode() {}
+func (*UnexportDir) dirNode() {}
+
+// Pos implements Node
+func (d *UnexportDir) Pos() token.Pos {
+	return d.Unexport
+}
+
+// End implements Node
+func (d *UnexportDir) End() token.Pos {
+	if n := len(d.Names); n > 0 {
+		return d.Names[n-1].End()
+	} else {
+		return d.Unexport + 8 // pos + len("unexport")
+	}
+}
+
+// UndefineDir represents an `undefine` directive. [Undefine Directive]
+//
+// [Undefine Directive]: https://www.gnu.org/software/make/manual/html_node/Undefine-Directive.html
+type UndefineDir struct {
+	Modifiers []*Modifier // override modifier, if it exists
+	Undefine  token.Pos   // position of UNDEFINE
+	Name      Expr        // variable name
+	Comment   *Comment    // trailing line comment, or nil
+}
+
+func (*UndefineDir) objNode() {}
+func (*UndefineDir) dirNode() {}
+
+// Pos implements Node
+func (d *UndefineDir) Pos() token.Pos {
+	if len(d.Modifiers) > 0 {
+		return d.Modifiers[0].Pos()
+	} else {
+		return d.Undefine
+	}
+}
+
+// End implements Node
+func (d *UndefineDir) End() token.Pos {
+	return d.Name.End()
+}
+
+// VpathDir represents a `vpath` directive. [Selective Search]
+// A VpathDir with no Directories clears the search paths for Pattern,
+// or all search paths when Pattern is also nil.
+//
+// [Selective Search]: https://www.gnu.org/software/make/manual/html_node/Selective-Search.html
+type VpathDir struct {
+	Vpath       token.Pos   // position of VPATH
+	Pattern     Expr        // file name pattern, or nil
+	Directories []Expr      // directory expressions
+	Colons      []token.Pos // positions of ':' separating directories
+	Comment     *Comment    // trailing line comment, or nil
+}
+
+func (*VpathDir) objNode() {}
+func (*VpathDir) dirNode() {}
+
+// Pos implements Node
+func (d *VpathDir) Pos() token.Pos {
+	return d.Vpath
+}
+
+// End implements Node
+func (d *VpathDir) End() token.Pos {
+	end := d.Vpath + 5 // pos + len("vpath")
+	if d.Pattern != nil {
+		end = d.Pattern.End()
+	}
+	if n := len(d.Directories); n > 0 {
+		end = d.Directories[n-1].End()
+	}
+	if n := len(d.Colons); n > 0 && d.Colons[n-1] >= end {
+		end = d.Colons[n-1] + 1
+	}
+
+	return end
+}
@@ -89,6 +89,24 @@ var _ = Describe("Ast", func() {
 		})
 	})
 
+	Describe("BadObj", func() {
+		It("should return the position range of the bad object", func() {
+			o := &ast.BadObj{From: token.Pos(4), To: token.Pos(20)}
+
+			Expect(o.Pos()).To(Equal(token.Pos(4)))
+			Expect(o.End()).To(Equal(token.Pos(20)))
+		})
+	})
+
+	Describe("BadExpr", func() {
+		It("should return the position range of the bad expression", func() {
+			x := &ast.BadExpr{From: token.Pos(4), To: token.Pos(6)}
+
+			Expect(x.Pos()).To(Equal(token.Pos(4)))
+			Expect(x.End()).To(Equal(token.Pos(6)))
+		})
+	})
+
 	Describe("Rule", func() {
 		It("should return the position of the first target", func() {
 			c := &ast.Rule{Targets: []ast.Expr{
@@ -98,6 +116,12 @@ var _ = Describe("Ast", func() {
 			Expect(c.Pos()).To(Equal(token.Pos(69)))
 		})
 
+		It("should return the position of the colon without targets", func() {
+			c := &ast.Rule{Colon: token.Pos(69)}
+
+			Expect(c.Pos()).To(Equal(token.Pos(69)))
+		})
+
 		It("should return the position after the colon", func() {
 			r := &ast.Rule{
 				Targets: []ast.Expr{&ast.Text{Value: "test"}},
@@ -107,6 +131,48 @@ var _ = Describe("Ast", func() {
 			Expect(r.End()).To(Equal(token.Pos(6)))
 		})
 
+		It("should return the position after a double colon", func() {
+			r := &ast.Rule{
+				Targets: []ast.Expr{&ast.Text{Value: "test"}},
+				Tok:     token.DOUBLE_COLON,
+				Colon:   5,
+			}
+
+			Expect(r.End()).To(Equal(token.Pos(7)))
+		})
+
+		It("should return the position after a grouped target separator", func() {
+			r := &ast.Rule{
+				Targets: []ast.Expr{&ast.Text{Value: "test"}},
+				Tok:     token.AND_COLON,
+				Colon:   6,
+			}
+
+			Expect(r.End()).To(Equal(token.Pos(8)))
+		})
+
+		DescribeTable("should report whether targets are grouped",
+			Entry(nil, token.COLON, false),
+			Entry(nil, token.DOUBLE_COLON, false),
+			Entry(nil, token.AND_COLON, true),
+			func(tok token.Token, expected bool) {
+				r := &ast.Rule{Tok: tok}
+
+				Expect(r.Grouped()).To(Equal(expected))
+			},
+		)
+
+		It("should return the position after the target-pattern colon", func() {
+			r := &ast.Rule{
+				Targets:      []ast.Expr{&ast.Text{Value: "test"}},
+				Colon:        5,
+				Pattern:      []ast.Expr{&ast.Text{Value: "%", ValuePos: 7}},
+				PatternColon: 8,
+			}
+
+			Expect(r.End()).To(Equal(token.Pos(9)))
+		})
+
 		It("should return the position after the final pre-requisite", func() {
 			p := &ast.Text{Value: "test", ValuePos: 3}
 			r := &ast.Rule{PreReqs: []ast.Expr{p}}
@@ -126,7 +192,7 @@ var _ = Describe("Ast", func() {
 				PrefixPos: token.Pos(420),
 				Text:      ast.Text{Value: "some text"},
 			}
-			c := &ast.Rule{Recipes: []*ast.Recipe{r}}
+			c := &ast.Rule{Recipes: []ast.Obj{r}}
 
 			Expect(c.End()).To(Equal(r.End()))
 		})
@@ -174,11 +240,30 @@ var _ = Describe("Ast", func() {
 			It("should stringify", func() {
 				c := &ast.QuotedExpr{
 					Quote: quote,
-					Value: &ast.Text{Value: "foo"},
+					Value: []ast.Expr{&ast.Text{Value: "foo"}},
 				}
 
 				Expect(c.String()).To(Equal(fmt.Sprint(quote, "foo", quote)))
 			})
+
+			It("should stringify a list", func() {
+				c := &ast.QuotedExpr{
+					Quote: quote,
+					Open:  token.Pos(1),
+					Value: []ast.Expr{
+						&ast.Text{Value: "foo", ValuePos: token.Pos(2)},
+						&ast.VarRef{
+							Dollar: token.Pos(6),
+							Open:   token.LPAREN,
+							Name:   []ast.Expr{&ast.Text{Value: "BAR", ValuePos: token.Pos(8)}},
+							Close:  token.RPAREN,
+						},
+					},
+					Close: token.Pos(13),
+				}
+
+				Expect(c.String()).To(Equal(fmt.Sprint(quote, "foo $(BAR) ", quote)))
+			})
 		},
 	)
 
@@ -196,29 +281,82 @@ var _ = Describe("Ast", func() {
 			c := &ast.VarRef{
 				Dollar: token.Pos(420),
 				Open:   token.LPAREN,
-				Name:   "bar",
+				Name:   []ast.Expr{&ast.Text{Value: "bar", ValuePos: token.Pos(422)}},
 				Close:  token.RPAREN,
 			}
 
-			Expect(c.End()).To(Equal(token.Pos(425)))
+			Expect(c.End()).To(Equal(token.Pos(426)))
 		})
 
 		It("should return the position after the character", func() {
 			c := &ast.VarRef{
 				Dollar: token.Pos(420),
 				Open:   token.ILLEGAL,
-				Name:   "b",
+				Name:   []ast.Expr{&ast.Text{Value: "b", ValuePos: token.Pos(421)}},
 				Close:  token.ILLEGAL,
 			}
 
-			Expect(c.End()).To(Equal(token.Pos(421)))
+			Expect(c.End()).To(Equal(token.Pos(422)))
+		})
+
+		It("should return the position after a computed name", func() {
+			c := &ast.VarRef{
+				Dollar: token.Pos(420),
+				Open:   token.LPAREN,
+				Name: []ast.Expr{
+					&ast.VarRef{
+						Dollar: token.Pos(422),
+						Open:   token.LPAREN,
+						Name:   []ast.Expr{&ast.Text{Value: "A", ValuePos: token.Pos(424)}},
+						Close:  token.RPAREN,
+					},
+					&ast.Text{Value: "_B", ValuePos: token.Pos(426)},
+				},
+				Close: token.RPAREN,
+			}
+
+			Expect(c.End()).To(Equal(token.Pos(429)))
+		})
+
+		It("should return the position after the closing token position", func() {
+			c := &ast.VarRef{
+				Dollar:   token.Pos(420),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "bar", ValuePos: token.Pos(422)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(427),
+			}
+
+			Expect(c.End()).To(Equal(token.Pos(428)))
+		})
+
+		It("should stringify whitespace before the closing token", func() {
+			c := &ast.VarRef{
+				Dollar:   token.Pos(1),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "CC", ValuePos: token.Pos(3)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(6),
+			}
+
+			Expect(c.String()).To(Equal("$(CC )"))
+		})
+
+		It("should return the position after an empty name", func() {
+			c := &ast.VarRef{
+				Dollar: token.Pos(420),
+				Open:   token.LPAREN,
+				Close:  token.RPAREN,
+			}
+
+			Expect(c.End()).To(Equal(token.Pos(423)))
 		})
 
 		It("should stringify with parens", func() {
 			c := &ast.VarRef{
 				Dollar: token.Pos(1),
 				Open:   token.LPAREN,
-				Name:   "foo",
+				Name:   []ast.Expr{&ast.Text{Value: "foo", ValuePos: token.Pos(3)}},
 				Close:  token.RPAREN,
 			}
 
@@ -229,18 +367,37 @@ var _ = Describe("Ast", func() {
 			c := &ast.VarRef{
 				Dollar: token.Pos(1),
 				Open:   token.LBRACE,
-				Name:   "foo",
+				Name:   []ast.Expr{&ast.Text{Value: "foo", ValuePos: token.Pos(3)}},
 				Close:  token.RBRACE,
 			}
 
 			Expect(c.String()).To(Equal("${foo}"))
 		})
 
+		It("should stringify computed names", func() {
+			c := &ast.VarRef{
+				Dollar: token.Pos(1),
+				Open:   token.LPAREN,
+				Name: []ast.Expr{
+					&ast.VarRef{
+						Dollar: token.Pos(3),
+						Open:   token.LBRACE,
+						Name:   []ast.Expr{&ast.Text{Value: "ARCH", ValuePos: token.Pos(5)}},
+						Close:  token.RBRACE,
+					},
+					&ast.Text{Value: "_CFLAGS", ValuePos: token.Pos(10)},
+				},
+				Close: token.RPAREN,
+			}
+
+			Expect(c.String()).To(Equal("$(${ARCH}_CFLAGS)"))
+		})
+
 		It("should stringify single characters", func() {
 			c := &ast.VarRef{
 				Dollar: token.Pos(1),
 				Open:   token.ILLEGAL,
-				Name:   "f",
+				Name:   []ast.Expr{&ast.Text{Value: "f", ValuePos: token.Pos(2)}},
 				Close:  token.ILLEGAL,
 			}
 
@@ -248,6 +405,145 @@ var _ = Describe("Ast", func() {
 		})
 	})
 
+	Describe("SubstRef", func() {
+		It("should return the position of the dollar sign", func() {
+			err := quick.Check(func(p int) bool {
+				r := &ast.SubstRef{Dollar: token.Pos(p)}
+				return r.Pos() == token.Pos(p)
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the position after the closing token", func() {
+			r := &ast.SubstRef{
+				Dollar:   token.Pos(420),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "A", ValuePos: token.Pos(422)}},
+				Colon:    token.Pos(423),
+				From:     []ast.Expr{&ast.Text{Value: ".c", ValuePos: token.Pos(424)}},
+				Assign:   token.Pos(426),
+				To:       []ast.Expr{&ast.Text{Value: ".o", ValuePos: token.Pos(427)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(429),
+			}
+
+			Expect(r.End()).To(Equal(token.Pos(430)))
+		})
+
+		It("should stringify", func() {
+			r := &ast.SubstRef{
+				Dollar:   token.Pos(420),
+				Open:     token.LBRACE,
+				Name:     []ast.Expr{&ast.Text{Value: "A", ValuePos: token.Pos(422)}},
+				Colon:    token.Pos(423),
+				From:     []ast.Expr{&ast.Text{Value: ".c", ValuePos: token.Pos(424)}},
+				Assign:   token.Pos(426),
+				To:       []ast.Expr{&ast.Text{Value: ".o", ValuePos: token.Pos(427)}},
+				Close:    token.RBRACE,
+				ClosePos: token.Pos(429),
+			}
+
+			Expect(r.String()).To(Equal("${A:.c=.o}"))
+		})
+	})
+
+	Describe("FuncCall", func() {
+		It("should return the position of the dollar sign", func() {
+			err := quick.Check(func(p int) bool {
+				c := &ast.FuncCall{Dollar: token.Pos(p)}
+				return c.Pos() == token.Pos(p)
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the position after the closing token", func() {
+			c := &ast.FuncCall{
+				Dollar:   token.Pos(420),
+				Open:     token.LPAREN,
+				Name:     token.DIR,
+				NamePos:  token.Pos(422),
+				Args:     [][]ast.Expr{{&ast.Text{Value: "foo", ValuePos: token.Pos(426)}}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(429),
+			}
+
+			Expect(c.End()).To(Equal(token.Pos(430)))
+		})
+
+		It("should stringify", func() {
+			c := &ast.FuncCall{
+				Dollar:  token.Pos(1),
+				Open:    token.LPAREN,
+				Name:    token.SUBST,
+				NamePos: token.Pos(3),
+				Args: [][]ast.Expr{
+					{&ast.Text{Value: "a", ValuePos: token.Pos(9)}},
+					{&ast.Text{Value: "b", ValuePos: token.Pos(12)}},
+					{&ast.VarRef{
+						Dollar: token.Pos(14),
+						Open:   token.LPAREN,
+						Name:   []ast.Expr{&ast.Text{Value: "C", ValuePos: token.Pos(16)}},
+						Close:  token.RPAREN,
+					}},
+				},
+				Commas:   []token.Pos{10, 13},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(18),
+			}
+
+			Expect(c.String()).To(Equal("$(subst a, b,$(C))"))
+		})
+	})
+
+	Describe("Continuation", func() {
+		It("should return the position of the backslash", func() {
+			err := quick.Check(func(p int) bool {
+				c := &ast.Continuation{Backslash: token.Pos(p)}
+				return c.Pos() == token.Pos(p)
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the position after the indentation", func() {
+			c := &ast.Continuation{
+				Backslash: token.Pos(420),
+				Text:      "\\\n\t ",
+			}
+
+			Expect(c.End()).To(Equal(token.Pos(424)))
+		})
+
+		It("should stringify", func() {
+			c := &ast.Continuation{Text: "\\\n  "}
+
+			Expect(c.String()).To(Equal("\\\n  "))
+		})
+	})
+
+	Describe("Escape", func() {
+		It("should return the position of the first dollar sign", func() {
+			err := quick.Check(func(p int) bool {
+				e := &ast.Escape{Dollar: token.Pos(p)}
+				return e.Pos() == token.Pos(p)
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the position after the second dollar sign", func() {
+			e := &ast.Escape{Dollar: token.Pos(420)}
+
+			Expect(e.End()).To(Equal(token.Pos(422)))
+		})
+
+		It("should stringify", func() {
+			Expect((&ast.Escape{}).String()).To(Equal("$$"))
+		})
+	})
+
 	Describe("Recipe", func() {
 		It("should return the position of the tab", func() {
 			c := &ast.Recipe{
@@ -266,9 +562,44 @@ var _ = Describe("Ast", func() {
 
 			Expect(c.End()).To(Equal(token.Pos(423)))
 		})
+
+		It("should return the position after the text with modifiers", func() {
+			c := &ast.Recipe{
+				PrefixPos: token.Pos(420),
+				Prefix:    token.TAB,
+				Modifiers: "@-",
+				Text:      ast.Text{Value: "foo"},
+			}
+
+			Expect(c.End()).To(Equal(token.Pos(425)))
+		})
+
+		DescribeTable("modifiers",
+			Entry(nil, "", false, false, false),
+			Entry(nil, "@", true, false, false),
+			Entry(nil, "-", false, true, false),
+			Entry(nil, "+", false, false, true),
+			Entry(nil, "@ -+", true, true, true),
+			func(mods string, silent, ignoreErrors, always bool) {
+				r := &ast.Recipe{Modifiers: mods}
+
+				Expect(r.Silent()).To(Equal(silent))
+				Expect(r.IgnoreErrors()).To(Equal(ignoreErrors))
+				Expect(r.Always()).To(Equal(always))
+			},
+		)
 	})
 
 	Describe("Variable", func() {
+		It("should return the position of the first modifier", func() {
+			v := &ast.Variable{
+				Modifiers: []*ast.Modifier{{Tok: token.EXPORT, TokPos: token.Pos(1)}},
+				Name:      &ast.Text{ValuePos: token.Pos(8)},
+			}
+
+			Expect(v.Pos()).To(Equal(token.Pos(1)))
+		})
+
 		It("should return the position of the name", func() {
 			err := quick.Check(func(n int) bool {
 				v := &ast.Variable{Name: &ast.Text{ValuePos: token.Pos(n)}}
@@ -300,6 +631,7 @@ var _ = Describe("Ast", func() {
 				Entry(":::=", token.IMMEDIATE_ASSIGN, 4),
 				Entry("?=", token.IFNDEF_ASSIGN, 2),
 				Entry("!=", token.SHELL_ASSIGN, 2),
+				Entry("+=", token.APPEND_ASSIGN, 2),
 				func(tok token.Token, l int) {
 					err := quick.Check(func(n int) bool {
 						v := &ast.Variable{
@@ -316,6 +648,51 @@ var _ = Describe("Ast", func() {
 		})
 	})
 
+	Describe("Modifier", func() {
+		It("should return the position of the keyword", func() {
+			m := &ast.Modifier{Tok: token.EXPORT, TokPos: token.Pos(69)}
+
+			Expect(m.Pos()).To(Equal(token.Pos(69)))
+		})
+
+		DescribeTable("should return the position after the keyword",
+			Entry(nil, token.OVERRIDE, 8),
+			Entry(nil, token.EXPORT, 6),
+			Entry(nil, token.PRIVATE, 7),
+			func(tok token.Token, l int) {
+				m := &ast.Modifier{Tok: tok, TokPos: token.Pos(420)}
+
+				Expect(m.End()).To(Equal(token.Pos(420 + l)))
+			},
+		)
+	})
+
+	Describe("TargetVariable", func() {
+		It("should return the position of the first target", func() {
+			v := &ast.TargetVariable{
+				Targets: []ast.Expr{&ast.Text{ValuePos: token.Pos(69)}},
+			}
+
+			Expect(v.Pos()).To(Equal(token.Pos(69)))
+		})
+
+		It("should return the position after the variable", func() {
+			v := &ast.Variable{
+				Name:  &ast.Text{Value: "A", ValuePos: token.Pos(4)},
+				Op:    token.RECURSIVE_ASSIGN,
+				OpPos: token.Pos(6),
+				Value: []ast.Expr{&ast.Text{Value: "b", ValuePos: token.Pos(8)}},
+			}
+			tv := &ast.TargetVariable{
+				Targets:  []ast.Expr{&ast.Text{Value: "t", ValuePos: token.Pos(1)}},
+				Colon:    token.Pos(2),
+				Variable: v,
+			}
+
+			Expect(tv.End()).To(Equal(token.Pos(9)))
+		})
+	})
+
 	Describe("IfeqDir", func() {
 		It("should return the position of the directive token", func() {
 			err := quick.Check(func(n int) bool {
@@ -342,13 +719,19 @@ var _ = Describe("Ast", func() {
 
 		It("should return the position after the second arg", func() {
 			err := quick.Check(func(n int) bool {
-				d := &ast.IfeqDir{Arg2: &ast.Text{ValuePos: token.Pos(n)}}
+				d := &ast.IfeqDir{Arg2: []ast.Expr{&ast.Text{ValuePos: token.Pos(n)}}}
 
 				return d.End() == token.Pos(n)
 			}, nil)
 
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should return the position after the directive token without arguments", func() {
+			d := &ast.IfeqDir{Tok: token.IFNEQ, TokPos: token.Pos(1)}
+
+			Expect(d.End()).To(Equal(token.Pos(6)))
+		})
 	})
 
 	Describe("IfdefDir", func() {
@@ -445,4 +828,169 @@ var _ = Describe("Ast", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Describe("DefineDir", func() {
+		It("should return the position of the define directive", func() {
+			err := quick.Check(func(n int) bool {
+				d := &ast.DefineDir{Define: token.Pos(n)}
+
+				return d.Pos() == token.Pos(n)
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the position of the first modifier", func() {
+			d := &ast.DefineDir{
+				Modifiers: []*ast.Modifier{{Tok: token.EXPORT, TokPos: token.Pos(69)}},
+				Define:    token.Pos(76),
+			}
+
+			Expect(d.Pos()).To(Equal(token.Pos(69)))
+		})
+
+		It("should return the position after the endef directive", func() {
+			err := quick.Check(func(n int) bool {
+				d := &ast.DefineDir{Endef: token.Pos(n)}
+
+				return d.End() == token.Pos(n+5)
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("ExportDir", func() {
+		It("should return the position of export", func() {
+			d := &ast.ExportDir{Export: token.Pos(3)}
+
+			Expect(d.Pos()).To(Equal(token.Pos(3)))
+		})
+
+		It("should return the position after the last name", func() {
+			d := &ast.ExportDir{
+				Export: token.Pos(1),
+				Names:  []ast.Expr{&ast.Text{Value: "FOO", ValuePos: token.Pos(8)}},
+			}
+
+			Expect(d.End()).To(Equal(token.Pos(11)))
+		})
+
+		It("should return the position after export when there are no names", func() {
+			d := &ast.ExportDir{Export: token.Pos(1)}
+
+			Expect(d.End()).To(Equal(token.Pos(7)))
+		})
+	})
+
+	Describe("UnexportDir", func() {
+		It("should return the position after unexport when there are no names", func() {
+			d := &ast.UnexportDir{Unexport: token.Pos(1)}
+
+			Expect(d.Pos()).To(Equal(token.Pos(1)))
+			Expect(d.End()).To(Equal(token.Pos(9)))
+		})
+	})
+
+	Describe("UndefineDir", func() {
+		It("should return the position of the first modifier", func() {
+			d := &ast.UndefineDir{
+				Modifiers: []*ast.Modifier{{Tok: token.OVERRIDE, TokPos: token.Pos(1)}},
+				Undefine:  token.Pos(10),
+				Name:      &ast.Text{Value: "FOO", ValuePos: token.Pos(19)},
+			}
+
+			Expect(d.Pos()).To(Equal(token.Pos(1)))
+			Expect(d.End()).To(Equal(token.Pos(22)))
+		})
+	})
+
+	Describe("VpathDir", func() {
+		It("should return the position of vpath", func() {
+			d := &ast.VpathDir{Vpath: token.Pos(4)}
+
+			Expect(d.Pos()).To(Equal(token.Pos(4)))
+		})
+
+		It("should return the position after vpath when clearing all search paths", func() {
+			d := &ast.VpathDir{Vpath: token.Pos(1)}
+
+			Expect(d.End()).To(Equal(token.Pos(6)))
+		})
+
+		It("should return the position after the pattern", func() {
+			d := &ast.VpathDir{
+				Vpath:   token.Pos(1),
+				Pattern: &ast.Text{Value: "%.c", ValuePos: token.Pos(7)},
+			}
+
+			Expect(d.End()).To(Equal(token.Pos(10)))
+		})
+
+		It("should return the position after the last directory", func() {
+			d := &ast.VpathDir{
+				Vpath:       token.Pos(1),
+				Pattern:     &ast.Text{Value: "%.c", ValuePos: token.Pos(7)},
+				Directories: []ast.Expr{&ast.Text{Value: "src", ValuePos: token.Pos(11)}},
+			}
+
+			Expect(d.End()).To(Equal(token.Pos(14)))
+		})
+
+		It("should return the position after a trailing colon", func() {
+			d := &ast.VpathDir{
+				Vpath:       token.Pos(1),
+				Pattern:     &ast.Text{Value: "%.c", ValuePos: token.Pos(7)},
+				Directories: []ast.Expr{&ast.Text{Value: "src", ValuePos: token.Pos(11)}},
+				Colons:      []token.Pos{token.Pos(14)},
+			}
+
+			Expect(d.End()).To(Equal(token.Pos(15)))
+		})
+	})
+
+	Describe("IncludeDir", func() {
+		It("should return the position of the directive token", func() {
+			err := quick.Check(func(n int) bool {
+				d := &ast.IncludeDir{
+					Tok:    token.INCLUDE,
+					TokPos: token.Pos(n),
+				}
+
+				return d.Pos() == token.Pos(n)
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the position after the last file", func() {
+			err := quick.Check(func(n int) bool {
+				d := &ast.IncludeDir{Files: []ast.Expr{
+					&ast.Text{ValuePos: token.Pos(n), Value: "foo.mk"},
+				}}
+
+				return d.End() == token.Pos(n+6)
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("should return the position after the directive token",
+			Entry("include", token.INCLUDE, 7),
+			Entry("-include", token.DASH_INCLUDE, 8),
+			Entry("sinclude", token.SINCLUDE, 8),
+			func(tok token.Token, l int) {
+				err := quick.Check(func(n int) bool {
+					d := &ast.IncludeDir{
+						Tok:    tok,
+						TokPos: token.Pos(n),
+					}
+
+					return d.End() == token.Pos(n+l)
+				}, nil)
+
+				Expect(err).NotTo(HaveOccurred())
+			},
+		)
+	})
 })
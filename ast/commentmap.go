@@ -0,0 +1,47 @@
+package ast
+
+import "github.com/unmango/go-make/token"
+
+// A CommentMap maps a Rule or Variable to the list of comment groups
+// associated with it. It is modeled after [go/ast.CommentMap].
+//
+// [go/ast.CommentMap]: https://pkg.go.dev/go/ast#CommentMap
+type CommentMap map[Node][]*CommentGroup
+
+// NewCommentMap creates a new comment map by associating each comment group
+// in the object lists of node with the Rule or Variable that directly follows
+// it, i.e. one that begins on the line after the comment group ends.
+// Comment groups remain in their object lists and are not removed from node.
+// file is used to compute line numbers and must be the file node was parsed from.
+func NewCommentMap(file *token.File, node Node) CommentMap {
+	cmap := CommentMap{}
+	Inspect(node, func(n Node) bool {
+		switch n := n.(type) {
+		case *File:
+			cmap.addList(file, n.Contents)
+		case *IfBlock:
+			cmap.addList(file, n.Text)
+		case *ElseBlock:
+			cmap.addList(file, n.Text)
+		}
+		return true
+	})
+
+	return cmap
+}
+
+func (cmap CommentMap) addList(file *token.File, l []Obj) {
+	for i := 1; i < len(l); i++ {
+		g, ok := l[i-1].(*CommentGroup)
+		if !ok {
+			continue
+		}
+
+		switch n := l[i].(type) {
+		case *Rule, *Variable:
+			if file.Line(g.End())+1 == file.Line(n.Pos()) {
+				cmap[n] = append(cmap[n], g)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package ast_test
+
+import (
+	"bytes"
+	gotoken "go/token"
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/unmango/go-make/ast"
+	"github.com/unmango/go-make/parser"
+	"github.com/unmango/go-make/token"
+)
+
+var _ = Describe("CommentMap", func() {
+	var file *token.File
+
+	BeforeEach(func() {
+		file = gotoken.NewFileSet().AddFile("test", 1, math.MaxInt-2)
+	})
+
+	parse := func(text string) *ast.File {
+		f, err := parser.New(bytes.NewBufferString(text), file, parser.WithMode(parser.ParseComments)).ParseFile()
+		Expect(err).NotTo(HaveOccurred())
+		return f
+	}
+
+	It("should associate a comment group with the following rule", func() {
+		f := parse("# Build the thing\nbuild:\n")
+
+		cmap := ast.NewCommentMap(file, f)
+
+		Expect(cmap).To(HaveLen(1))
+		Expect(cmap[f.Contents[1]]).To(HaveExactElements(f.Contents[0]))
+	})
+
+	It("should associate a comment group with the following variable", func() {
+		f := parse("# The thing\nVAR := thing\n")
+
+		cmap := ast.NewCommentMap(file, f)
+
+		Expect(cmap).To(HaveLen(1))
+		Expect(cmap[f.Contents[1]]).To(HaveExactElements(f.Contents[0]))
+	})
+
+	It("should not associate a comment group separated by an empty line", func() {
+		f := parse("# Unrelated\n\nbuild:\n")
+
+		cmap := ast.NewCommentMap(file, f)
+
+		Expect(cmap).To(BeEmpty())
+	})
+
+	It("should associate a comment group with a rule without targets", func() {
+		f := parse("# c\nx: foo\n")
+		r, ok := f.Contents[1].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		r.Targets = nil
+
+		cmap := ast.NewCommentMap(file, f)
+
+		Expect(cmap[r]).To(HaveExactElements(f.Contents[0]))
+	})
+
+	It("should associate comment groups in conditional blocks", func() {
+		f := parse("ifdef CI\n# On CI\nbuild:\nelse\n# Locally\nbuild:\nendif\n")
+		b, ok := f.Contents[0].(*ast.IfBlock)
+		Expect(ok).To(BeTrue())
+
+		cmap := ast.NewCommentMap(file, f)
+
+		Expect(cmap).To(HaveLen(2))
+		Expect(cmap[b.Text[1]]).To(HaveExactElements(b.Text[0]))
+		Expect(cmap[b.Else[0].Text[1]]).To(HaveExactElements(b.Else[0].Text[0]))
+	})
+})
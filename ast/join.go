@@ -0,0 +1,55 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unmango/go-make/token"
+)
+
+// Join returns the logical value of l as make reads it outside of a recipe. [Splitting Lines]
+// Each [Continuation], along with any whitespace surrounding it, is replaced
+// by a single space. Other whitespace between expressions is preserved.
+//
+// [Splitting Lines]: https://www.gnu.org/software/make/manual/html_node/Splitting-Lines.html
+func Join(l []Expr) string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	b := &strings.Builder{}
+	_ = writeList(b, l[0].Pos(), l)
+
+	return b.String()
+}
+
+// writeList writes l to b, restoring whitespace between expressions from
+// their positions beginning at pos, and returns the end of the last expression.
+func writeList(b *strings.Builder, pos token.Pos, l []Expr) token.Pos {
+	var joined bool
+	for _, e := range l {
+		if _, ok := e.(*Continuation); ok {
+			joined = true
+		} else {
+			if !joined {
+				writeGap(b, pos, e.Pos())
+			} else if b.Len() > 0 {
+				b.WriteString(" ")
+			}
+
+			fmt.Fprint(b, e)
+			joined = false
+		}
+
+		pos = e.End()
+	}
+
+	return pos
+}
+
+// writeGap writes a space to b for each position between pos and end.
+func writeGap(b *strings.Builder, pos, end token.Pos) {
+	if pos.IsValid() && end > pos {
+		b.WriteString(strings.Repeat(" ", int(end-pos)))
+	}
+}
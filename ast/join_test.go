@@ -0,0 +1,86 @@
+package ast_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/unmango/go-make/ast"
+	"github.com/unmango/go-make/token"
+)
+
+var _ = Describe("Join", func() {
+	It("should return an empty string for an empty list", func() {
+		Expect(ast.Join(nil)).To(BeEmpty())
+	})
+
+	It("should preserve whitespace between expressions", func() {
+		l := []ast.Expr{
+			&ast.Text{Value: "a", ValuePos: token.Pos(1)},
+			&ast.Text{Value: "b", ValuePos: token.Pos(4)},
+		}
+
+		Expect(ast.Join(l)).To(Equal("a  b"))
+	})
+
+	It("should join adjacent expressions", func() {
+		l := []ast.Expr{
+			&ast.Text{Value: "a", ValuePos: token.Pos(1)},
+			&ast.VarRef{
+				Dollar: token.Pos(2),
+				Open:   token.ILLEGAL,
+				Name:   []ast.Expr{&ast.Text{Value: "@", ValuePos: token.Pos(3)}},
+				Close:  token.ILLEGAL,
+			},
+		}
+
+		Expect(ast.Join(l)).To(Equal("a$@"))
+	})
+
+	DescribeTable("should collapse a continuation and its surrounding whitespace",
+		Entry("with indentation", []ast.Expr{
+			&ast.Text{Value: "a", ValuePos: token.Pos(1)},
+			&ast.Continuation{Backslash: token.Pos(5), Text: "\\\n\t  "},
+			&ast.Text{Value: "b", ValuePos: token.Pos(10)},
+		}),
+		Entry("without whitespace", []ast.Expr{
+			&ast.Text{Value: "a", ValuePos: token.Pos(1)},
+			&ast.Continuation{Backslash: token.Pos(2), Text: "\\\n"},
+			&ast.Text{Value: "b", ValuePos: token.Pos(4)},
+		}),
+		Entry("with consecutive continuations", []ast.Expr{
+			&ast.Text{Value: "a", ValuePos: token.Pos(1)},
+			&ast.Continuation{Backslash: token.Pos(3), Text: "\\\n"},
+			&ast.Continuation{Backslash: token.Pos(5), Text: "\\\n "},
+			&ast.Text{Value: "b", ValuePos: token.Pos(8)},
+		}),
+		func(l []ast.Expr) {
+			Expect(ast.Join(l)).To(Equal("a b"))
+		},
+	)
+
+	It("should drop a leading continuation", func() {
+		l := []ast.Expr{
+			&ast.Continuation{Backslash: token.Pos(1), Text: "\\\n  "},
+			&ast.Text{Value: "a", ValuePos: token.Pos(5)},
+		}
+
+		Expect(ast.Join(l)).To(Equal("a"))
+	})
+
+	It("should collapse continuations in function arguments", func() {
+		l := []ast.Expr{&ast.FuncCall{
+			Dollar:  token.Pos(1),
+			Open:    token.LPAREN,
+			Name:    token.DIR,
+			NamePos: token.Pos(3),
+			Args: [][]ast.Expr{{
+				&ast.Continuation{Backslash: token.Pos(7), Text: "\\\n  "},
+				&ast.Text{Value: "foo", ValuePos: token.Pos(11)},
+			}},
+			Close:    token.RPAREN,
+			ClosePos: token.Pos(14),
+		}}
+
+		Expect(ast.Join(l)).To(Equal("$(dir foo)"))
+	})
+})
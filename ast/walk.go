@@ -25,30 +25,109 @@ func Walk(v Visitor, node Node) {
 		walkList(v, n.List)
 	case *Rule:
 		walkList(v, n.Targets)
+		walkList(v, n.Pattern)
 		walkList(v, n.PreReqs)
 		walkList(v, n.OrderPreReqs)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
 		walkList(v, n.Recipes)
 	case *Recipe:
-		Walk(v, &n.Text)
+		if n.Exprs != nil {
+			walkList(v, n.Exprs)
+		} else {
+			Walk(v, &n.Text)
+		}
 	case *QuotedExpr:
-		Walk(v, n.Value)
+		walkList(v, n.Value)
+	case *VarRef:
+		walkList(v, n.Name)
+	case *SubstRef:
+		walkList(v, n.Name)
+		walkList(v, n.From)
+		walkList(v, n.To)
+	case *FuncCall:
+		for _, arg := range n.Args {
+			walkList(v, arg)
+		}
 	case *Variable:
+		walkList(v, n.Modifiers)
 		if n.Name != nil {
 			Walk(v, n.Name)
 		}
 		walkList(v, n.Value)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
+	case *TargetVariable:
+		walkList(v, n.Targets)
+		if n.Variable != nil {
+			Walk(v, n.Variable)
+		}
 	case *IfeqDir:
-		Walk(v, n.Arg1)
-		Walk(v, n.Arg2)
+		walkList(v, n.Arg1)
+		walkList(v, n.Arg2)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
 	case *IfdefDir:
 		Walk(v, n.VarName)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
 	case *ElseBlock:
 		Walk(v, n.Condition)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
 		walkList(v, n.Text)
 	case *IfBlock:
 		Walk(v, n.Directive)
 		walkList(v, n.Text)
 		walkList(v, n.Else)
+		if n.EndifComment != nil {
+			Walk(v, n.EndifComment)
+		}
+	case *DefineDir:
+		walkList(v, n.Modifiers)
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		walkList(v, n.Body)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
+	case *IncludeDir:
+		walkList(v, n.Files)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
+	case *ExportDir:
+		walkList(v, n.Names)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
+	case *UnexportDir:
+		walkList(v, n.Names)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
+	case *VpathDir:
+		if n.Pattern != nil {
+			Walk(v, n.Pattern)
+		}
+		walkList(v, n.Directories)
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
+	case *UndefineDir:
+		walkList(v, n.Modifiers)
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
 	}
 }
 
@@ -119,6 +119,22 @@ var _ = Describe("Walk", func() {
 		Expect(v.nodes).To(HaveExactElements(rule, p3, p4, p1, p2))
 	})
 
+	It("should walk a static pattern rule", func() {
+		v := &visitor{}
+		t := &ast.Text{}
+		tp := &ast.Text{}
+		pr := &ast.Text{}
+		r := &ast.Rule{
+			Targets: []ast.Expr{t},
+			Pattern: []ast.Expr{tp},
+			PreReqs: []ast.Expr{pr},
+		}
+
+		ast.Walk(v, r)
+
+		Expect(v.nodes).To(HaveExactElements(r, t, tp, pr))
+	})
+
 	It("should walk a rule with targets and recipes", func() {
 		v := &visitor{}
 		t1 := ast.Text{}
@@ -126,7 +142,7 @@ var _ = Describe("Walk", func() {
 		r1 := &ast.Recipe{Text: t1}
 		rule := &ast.Rule{
 			Targets: []ast.Expr{t2},
-			Recipes: []*ast.Recipe{r1},
+			Recipes: []ast.Obj{r1},
 		}
 
 		ast.Walk(v, rule)
@@ -144,6 +160,77 @@ var _ = Describe("Walk", func() {
 		Expect(v.nodes).To(HaveExactElements(r1, &t1))
 	})
 
+	It("should walk a recipe with expressions", func() {
+		v := &visitor{}
+		t1 := &ast.Text{}
+		ref := &ast.VarRef{}
+		r1 := &ast.Recipe{Exprs: []ast.Expr{t1, ref}}
+
+		ast.Walk(v, r1)
+
+		Expect(v.nodes).To(HaveExactElements(r1, t1, ref))
+	})
+
+	It("should walk a rule with a trailing comment", func() {
+		v := &visitor{}
+		p1 := &ast.Text{}
+		c := &ast.Comment{}
+		r1 := &ast.Recipe{}
+		rule := &ast.Rule{
+			PreReqs: []ast.Expr{p1},
+			Comment: c,
+			Recipes: []ast.Obj{r1},
+		}
+
+		ast.Walk(v, rule)
+
+		Expect(v.nodes).To(HaveExactElements(rule, p1, c, r1, &r1.Text))
+	})
+
+	It("should walk a variable with modifiers", func() {
+		v := &visitor{}
+		m := &ast.Modifier{}
+		name := &ast.Text{}
+		variable := &ast.Variable{
+			Modifiers: []*ast.Modifier{m},
+			Name:      name,
+		}
+
+		ast.Walk(v, variable)
+
+		Expect(v.nodes).To(HaveExactElements(variable, m, name))
+	})
+
+	It("should walk a vpath directive", func() {
+		v := &visitor{}
+		pattern := &ast.Text{}
+		dir := &ast.Text{}
+		d := &ast.VpathDir{
+			Pattern:     pattern,
+			Directories: []ast.Expr{dir},
+		}
+
+		ast.Walk(v, d)
+
+		Expect(v.nodes).To(HaveExactElements(d, pattern, dir))
+	})
+
+	It("should walk a variable with a trailing comment", func() {
+		v := &visitor{}
+		name := &ast.Text{}
+		value := &ast.Text{}
+		c := &ast.Comment{}
+		variable := &ast.Variable{
+			Name:    name,
+			Value:   []ast.Expr{value},
+			Comment: c,
+		}
+
+		ast.Walk(v, variable)
+
+		Expect(v.nodes).To(HaveExactElements(variable, name, value, c))
+	})
+
 	It("should walk text", func() {
 		v := &visitor{}
 		t1 := &ast.Text{}
@@ -162,6 +249,17 @@ var _ = Describe("Walk", func() {
 		Expect(v.nodes).To(HaveExactElements(v1))
 	})
 
+	It("should walk a computed variable reference", func() {
+		v := &visitor{}
+		v2 := &ast.VarRef{}
+		t1 := &ast.Text{}
+		v1 := &ast.VarRef{Name: []ast.Expr{v2, t1}}
+
+		ast.Walk(v, v1)
+
+		Expect(v.nodes).To(HaveExactElements(v1, v2, t1))
+	})
+
 	It("should walk a comment group", func() {
 		v := &visitor{}
 		c := &ast.Comment{}
@@ -186,7 +284,7 @@ var _ = Describe("Walk", func() {
 	It("should walk a quoted expression", func() {
 		v := &visitor{}
 		t1 := &ast.Text{}
-		q := &ast.QuotedExpr{Value: t1}
+		q := &ast.QuotedExpr{Value: []ast.Expr{t1}}
 
 		ast.Walk(v, q)
 
@@ -218,11 +316,26 @@ var _ = Describe("Walk", func() {
 		Expect(v.nodes).To(HaveExactElements(v1, t1, t2, t3))
 	})
 
+	It("should walk a target variable", func() {
+		v := &visitor{}
+		t1 := &ast.Text{}
+		m := &ast.Modifier{}
+		v1 := &ast.Variable{Modifiers: []*ast.Modifier{m}}
+		tv := &ast.TargetVariable{
+			Targets:  []ast.Expr{t1},
+			Variable: v1,
+		}
+
+		ast.Walk(v, tv)
+
+		Expect(v.nodes).To(HaveExactElements(tv, t1, v1, m))
+	})
+
 	It("should walk an ifeq directive", func() {
 		v := &visitor{}
 		t1 := &ast.Text{}
 		t2 := &ast.Text{}
-		d := &ast.IfeqDir{Arg1: t1, Arg2: t2}
+		d := &ast.IfeqDir{Arg1: []ast.Expr{t1}, Arg2: []ast.Expr{t2}}
 
 		ast.Walk(v, d)
 
@@ -271,6 +384,65 @@ var _ = Describe("Walk", func() {
 		Expect(v.nodes).To(HaveExactElements(f, d2, t2, v2, e, d1, t1, v1))
 	})
 
+	It("should walk a define directive", func() {
+		v := &visitor{}
+		t1 := &ast.Text{}
+		t2 := &ast.Text{}
+		t3 := &ast.Text{}
+		c := &ast.Comment{}
+		m := &ast.Modifier{}
+		d := &ast.DefineDir{
+			Modifiers: []*ast.Modifier{m},
+			Name:      t1,
+			Body:      []*ast.Text{t2, t3},
+			Comment:   c,
+		}
+
+		ast.Walk(v, d)
+
+		Expect(v.nodes).To(HaveExactElements(d, m, t1, t2, t3, c))
+	})
+
+	It("should walk an include directive", func() {
+		v := &visitor{}
+		t1 := &ast.Text{}
+		t2 := &ast.Text{}
+		c := &ast.Comment{}
+		d := &ast.IncludeDir{Files: []ast.Expr{t1, t2}, Comment: c}
+
+		ast.Walk(v, d)
+
+		Expect(v.nodes).To(HaveExactElements(d, t1, t2, c))
+	})
+
+	It("should walk a substitution reference", func() {
+		v := &visitor{}
+		t1 := &ast.Text{}
+		t2 := &ast.Text{}
+		t3 := &ast.Text{}
+		r := &ast.SubstRef{
+			Name: []ast.Expr{t1},
+			From: []ast.Expr{t2},
+			To:   []ast.Expr{t3},
+		}
+
+		ast.Walk(v, r)
+
+		Expect(v.nodes).To(HaveExactElements(r, t1, t2, t3))
+	})
+
+	It("should walk a function call", func() {
+		v := &visitor{}
+		t1 := &ast.Text{}
+		t2 := &ast.Text{}
+		r := &ast.VarRef{}
+		c := &ast.FuncCall{Args: [][]ast.Expr{{t1, t2}, nil, {r}}}
+
+		ast.Walk(v, c)
+
+		Expect(v.nodes).To(HaveExactElements(c, t1, t2, r))
+	})
+
 	Describe("Inspect", func() {
 		It("should inspect nil", func() {
 			var nodes []ast.Node
@@ -407,7 +579,7 @@ var _ = Describe("Walk", func() {
 			r1 := &ast.Recipe{Text: t1}
 			rule := &ast.Rule{
 				Targets: []ast.Expr{t2},
-				Recipes: []*ast.Recipe{r1},
+				Recipes: []ast.Obj{r1},
 			}
 
 			ast.Inspect(rule, func(n ast.Node) bool {
@@ -485,7 +657,7 @@ var _ = Describe("Walk", func() {
 		It("should inspect a quoted expression", func() {
 			var nodes []ast.Node
 			t1 := &ast.Text{}
-			q := &ast.QuotedExpr{Value: t1}
+			q := &ast.QuotedExpr{Value: []ast.Expr{t1}}
 
 			ast.Inspect(q, func(n ast.Node) bool {
 				nodes = append(nodes, n)
@@ -530,7 +702,7 @@ var _ = Describe("Walk", func() {
 			var nodes []ast.Node
 			t1 := &ast.Text{}
 			t2 := &ast.Text{}
-			d := &ast.IfeqDir{Arg1: t1, Arg2: t2}
+			d := &ast.IfeqDir{Arg1: []ast.Expr{t1}, Arg2: []ast.Expr{t2}}
 
 			ast.Inspect(d, func(n ast.Node) bool {
 				nodes = append(nodes, n)
@@ -690,7 +862,7 @@ var _ = Describe("Walk", func() {
 			r1 := &ast.Recipe{Text: t1}
 			rule := &ast.Rule{
 				Targets: []ast.Expr{t2},
-				Recipes: []*ast.Recipe{r1},
+				Recipes: []ast.Obj{r1},
 			}
 
 			nodes := ast.Preorder(rule)
@@ -744,7 +916,7 @@ var _ = Describe("Walk", func() {
 
 		It("should sequence a quoted expression", func() {
 			t1 := &ast.Text{}
-			expr := &ast.QuotedExpr{Value: t1}
+			expr := &ast.QuotedExpr{Value: []ast.Expr{t1}}
 
 			nodes := ast.Preorder(expr)
 
@@ -777,7 +949,7 @@ var _ = Describe("Walk", func() {
 		It("should sequence an ifeq directive", func() {
 			t1 := &ast.Text{}
 			t2 := &ast.Text{}
-			directive := &ast.IfeqDir{Arg1: t1, Arg2: t2}
+			directive := &ast.IfeqDir{Arg1: []ast.Expr{t1}, Arg2: []ast.Expr{t2}}
 
 			nodes := ast.Preorder(directive)
 
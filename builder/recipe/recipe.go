@@ -6,12 +6,20 @@ import (
 )
 
 func Copy(pos token.Pos, r *ast.Recipe) *ast.Recipe {
+	mods := token.NoPos
+	if r.Modifiers != "" {
+		mods = pos + 1
+	}
+
 	return &ast.Recipe{
-		Prefix:    r.Prefix,
-		PrefixPos: pos,
+		Prefix:       r.Prefix,
+		PrefixPos:    pos,
+		PrefixLit:    r.PrefixLit,
+		Modifiers:    r.Modifiers,
+		ModifiersPos: mods,
 		Text: ast.Text{
 			Value:    r.Value,
-			ValuePos: pos + 1,
+			ValuePos: pos + 1 + token.Pos(len(r.Modifiers)),
 		},
 	}
 }
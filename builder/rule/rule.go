@@ -53,7 +53,7 @@ func TextTarget(value string) builder.Rule {
 }
 
 func Copy(pos token.Pos, r *ast.Rule) *ast.Rule {
-	rule := &ast.Rule{}
+	rule := &ast.Rule{Tok: r.Tok}
 	for _, t := range r.Targets {
 		t = expr.Copy(pos, t)
 		rule.Targets = append(rule.Targets, t)
@@ -65,7 +65,17 @@ func Copy(pos token.Pos, r *ast.Rule) *ast.Rule {
 	} else {
 		rule.Colon = pos
 	}
-	pos += 2
+	pos = rule.End() + 1
+
+	if r.PatternColon.IsValid() {
+		for _, p := range r.Pattern {
+			p = expr.Copy(pos, p)
+			rule.Pattern = append(rule.Pattern, p)
+			pos = p.End() + 1
+		}
+		rule.PatternColon = pos - 1
+		pos = rule.End() + 1
+	}
 
 	for _, p := range r.PreReqs {
 		p = expr.Copy(pos, p)
@@ -83,12 +93,127 @@ func Copy(pos token.Pos, r *ast.Rule) *ast.Rule {
 	}
 
 	if len(r.Recipes) > 0 {
-		for _, r := range r.Recipes {
-			r = recipe.Copy(pos, r)
-			rule.Recipes = append(rule.Recipes, r)
-			pos = r.End()
-		}
+		rule.Recipes, _ = copyRecipes(pos, r.Recipes)
 	}
 
 	return rule
 }
+
+// copyRecipes copies the recipe lines l to consecutive lines starting at pos
+// and returns the copies along with the position of the line following them.
+func copyRecipes(pos token.Pos, l []ast.Obj) ([]ast.Obj, token.Pos) {
+	var recipes []ast.Obj
+	for _, o := range l {
+		switch n := o.(type) {
+		case *ast.Recipe:
+			n = recipe.Copy(pos, n)
+			o, pos = n, n.Text.End()+1
+		case *ast.CommentGroup:
+			o, pos = copyCommentGroup(pos, n)
+		case *ast.IfBlock:
+			o, pos = copyIfBlock(pos, n)
+		default:
+			panic("unsupported node type")
+		}
+
+		recipes = append(recipes, o)
+	}
+
+	return recipes, pos
+}
+
+// copyComment copies the trailing comment c of a line ending at end
+// and returns the copy along with the new end of the line.
+func copyComment(end token.Pos, c *ast.Comment) (*ast.Comment, token.Pos) {
+	if c == nil {
+		return nil, end
+	}
+
+	c = &ast.Comment{Pound: end + 1, Text: c.Text}
+	return c, c.Pound + 2 + token.Pos(len(c.Text)) // '#' + ' ' + len(c.Text)
+}
+
+func copyCommentGroup(pos token.Pos, g *ast.CommentGroup) (*ast.CommentGroup, token.Pos) {
+	group := &ast.CommentGroup{}
+	for _, c := range g.List {
+		c, pos = copyComment(pos-1, c)
+		group.List = append(group.List, c)
+		pos++ // '\n'
+	}
+
+	return group, pos
+}
+
+func copyIfDir(pos token.Pos, d ast.IfDir) (ast.IfDir, token.Pos) {
+	switch n := d.(type) {
+	case *ast.IfdefDir:
+		dir := &ast.IfdefDir{
+			Tok:     n.Tok,
+			TokPos:  pos,
+			VarName: expr.Copy(pos+token.Pos(len(n.Tok.String()))+1, n.VarName),
+		}
+		dir.Comment, pos = copyComment(dir.End(), n.Comment)
+		return dir, pos
+	case *ast.IfeqDir:
+		dir := &ast.IfeqDir{Tok: n.Tok, TokPos: pos}
+		pos += token.Pos(len(n.Tok.String())) + 1
+		if n.Open.IsValid() {
+			dir.Open = pos
+			dir.Arg1, pos = copyArg(pos+1, n.Arg1)
+			dir.Comma = pos
+			dir.Arg2, pos = copyArg(pos+1, n.Arg2)
+			dir.Close = pos
+		} else {
+			dir.Arg1, pos = copyArg(pos, n.Arg1)
+			dir.Arg2, _ = copyArg(pos+1, n.Arg2)
+		}
+		dir.Comment, pos = copyComment(dir.End(), n.Comment)
+		return dir, pos
+	default:
+		panic("unsupported node type")
+	}
+}
+
+// copyArg copies the space separated expressions of a conditional argument
+// starting at pos and returns the copies along with the position following them.
+func copyArg(pos token.Pos, l []ast.Expr) ([]ast.Expr, token.Pos) {
+	var arg []ast.Expr
+	for i, e := range l {
+		if i > 0 {
+			pos++ // ' '
+		}
+
+		e = expr.Copy(pos, e)
+		arg = append(arg, e)
+		pos = e.End()
+	}
+
+	return arg, pos
+}
+
+func copyIfBlock(pos token.Pos, b *ast.IfBlock) (*ast.IfBlock, token.Pos) {
+	block := &ast.IfBlock{}
+	block.Directive, pos = copyIfDir(pos, b.Directive)
+	block.Text, pos = copyRecipes(pos+1, b.Text)
+
+	for _, e := range b.Else {
+		e, pos = copyElseBlock(pos, e)
+		block.Else = append(block.Else, e)
+	}
+
+	block.Endif = pos
+	block.EndifComment, pos = copyComment(block.End(), b.EndifComment)
+	return block, pos + 1
+}
+
+func copyElseBlock(pos token.Pos, b *ast.ElseBlock) (*ast.ElseBlock, token.Pos) {
+	block := &ast.ElseBlock{Else: pos}
+	pos += 4 // len("else")
+	if b.Condition != nil {
+		block.Condition, pos = copyIfDir(pos+1, b.Condition)
+	}
+
+	block.Comment, pos = copyComment(pos, b.Comment)
+	block.Text, pos = copyRecipes(pos+1, b.Text)
+	return block, pos
+}
@@ -1,11 +1,16 @@
 package rule_test
 
 import (
+	"bytes"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/unmango/go-make/ast"
 	"github.com/unmango/go-make/builder/rule"
+	"github.com/unmango/go-make/parser"
+	"github.com/unmango/go-make/printer"
 	"github.com/unmango/go-make/token"
 )
 
@@ -111,5 +116,131 @@ var _ = Describe("Rule", func() {
 				},
 			}))
 		})
+
+		DescribeTable("should copy the rule kind",
+			Entry(nil, token.COLON, token.Pos(8)),
+			Entry(nil, token.DOUBLE_COLON, token.Pos(9)),
+			Entry(nil, token.AND_COLON, token.Pos(9)),
+			func(tok token.Token, prereq token.Pos) {
+				r := rule.New(1, rule.TextTarget("test"), rule.TextPreReq("dep"))
+				r.Tok = tok
+
+				actual := rule.Copy(2, r)
+
+				Expect(actual).To(Equal(&ast.Rule{
+					Targets: []ast.Expr{
+						&ast.Text{Value: "test", ValuePos: 2},
+					},
+					Tok:   tok,
+					Colon: 6,
+					PreReqs: []ast.Expr{
+						&ast.Text{Value: "dep", ValuePos: prereq},
+					},
+				}))
+			},
+		)
+
+		It("should copy the target-pattern of a static pattern rule", func() {
+			r := rule.New(1, rule.TextTarget("a.o"), rule.TextPreReq("%.c"))
+			r.Pattern = []ast.Expr{&ast.Text{Value: "%.o"}}
+			r.PatternColon = token.Pos(8)
+
+			actual := rule.Copy(2, r)
+
+			Expect(actual).To(Equal(&ast.Rule{
+				Targets: []ast.Expr{
+					&ast.Text{Value: "a.o", ValuePos: 2},
+				},
+				Colon: 5,
+				Pattern: []ast.Expr{
+					&ast.Text{Value: "%.o", ValuePos: 7},
+				},
+				PatternColon: 10,
+				PreReqs: []ast.Expr{
+					&ast.Text{Value: "%.c", ValuePos: 12},
+				},
+			}))
+		})
+
+		It("should copy conditional directives in the recipes", func() {
+			r := &ast.Rule{Recipes: []ast.Obj{
+				&ast.Recipe{Prefix: token.TAB, Text: ast.Text{Value: "echo a"}},
+				&ast.IfBlock{
+					Directive: &ast.IfdefDir{Tok: token.IFDEF, VarName: &ast.Text{Value: "DEBUG"}},
+					Text: []ast.Obj{
+						&ast.Recipe{Prefix: token.TAB, Text: ast.Text{Value: "echo b"}},
+					},
+					Else: []*ast.ElseBlock{{
+						Comment: &ast.Comment{Text: "release"},
+					}},
+				},
+			}}
+
+			actual := rule.Copy(2, r)
+
+			Expect(actual.Recipes).To(Equal([]ast.Obj{
+				&ast.Recipe{
+					Prefix:    token.TAB,
+					PrefixPos: 4,
+					Text:      ast.Text{Value: "echo a", ValuePos: 5},
+				},
+				&ast.IfBlock{
+					Directive: &ast.IfdefDir{
+						Tok:     token.IFDEF,
+						TokPos:  12,
+						VarName: &ast.Text{Value: "DEBUG", ValuePos: 18},
+					},
+					Text: []ast.Obj{&ast.Recipe{
+						Prefix:    token.TAB,
+						PrefixPos: 24,
+						Text:      ast.Text{Value: "echo b", ValuePos: 25},
+					}},
+					Else: []*ast.ElseBlock{{
+						Else:    32,
+						Comment: &ast.Comment{Pound: 37, Text: "release"},
+					}},
+					Endif: 47,
+				},
+			}))
+		})
+
+		It("should copy comment groups in the recipes", func() {
+			r := &ast.Rule{Recipes: []ast.Obj{
+				&ast.CommentGroup{List: []*ast.Comment{{Text: "a"}, {Text: "bc"}}},
+				&ast.Recipe{Prefix: token.TAB, Text: ast.Text{Value: "echo"}},
+			}}
+
+			actual := rule.Copy(2, r)
+
+			Expect(actual.Recipes).To(Equal([]ast.Obj{
+				&ast.CommentGroup{List: []*ast.Comment{
+					{Pound: 4, Text: "a"},
+					{Pound: 8, Text: "bc"},
+				}},
+				&ast.Recipe{
+					Prefix:    token.TAB,
+					PrefixPos: 13,
+					Text:      ast.Text{Value: "echo", ValuePos: 14},
+				},
+			}))
+		})
+
+		DescribeTable("should print a copy of a parsed rule",
+			Entry(nil, "all:\n\techo a\nifdef X\n\techo b\nendif\n\techo c\n"),
+			Entry(nil, "all:\n\techo a\n# comment\n\techo b\n"),
+			Entry(nil, "all:\nifeq (a,b) # c\n\techo a\nelse ifdef Y\n\techo b\nelse # d\n\techo c\nendif # e\n"),
+			func(src string) {
+				file := token.NewFileSet().AddFile("", -1, len(src))
+				f, err := parser.New(strings.NewReader(src), file, parser.WithMode(parser.ParseComments)).ParseFile()
+				Expect(err).NotTo(HaveOccurred())
+
+				actual := rule.Copy(1, f.Contents[0].(*ast.Rule))
+
+				buf := &bytes.Buffer{}
+				_, err = printer.Fprint(buf, actual)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buf.String()).To(Equal(src))
+			},
+		)
 	})
 })
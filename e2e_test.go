@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/unmango/go-make"
+	"github.com/unmango/go-make/parser"
 	"github.com/unmango/go-make/printer"
 	"github.com/unmango/go-make/scanner"
 	"github.com/unmango/go-make/token"
@@ -39,7 +40,7 @@ var _ = Describe("E2E", func() {
 		}, "5s", "1ms").Should(Equal(token.EOF))
 	})
 
-	It("should parse this repo's Makefile", Pending, func() {
+	It("should parse this repo's Makefile", func() {
 		f, err := os.Open("Makefile")
 		Expect(err).NotTo(HaveOccurred())
 		fi, err := f.Stat()
@@ -54,7 +55,23 @@ var _ = Describe("E2E", func() {
 
 	DescribeTable("should round-trip", RoundTripEntries(testdata, "testdata/roundtrip"),
 		func(input string) {
-			p := make.NewParser(bytes.NewBufferString(input), nil)
+			p := make.NewParser(bytes.NewBufferString(input), nil, parser.WithMode(parser.ParseComments))
+
+			f, err := p.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+
+			buf := &bytes.Buffer{}
+			w := writer.New(buf)
+
+			Expect(printer.Fprint(w, f)).To(BeNumerically(">", 0))
+			Expect(buf.String()).To(Equal(input))
+		},
+	)
+
+	DescribeTable("should round-trip with structured recipes", RoundTripEntries(testdata, "testdata/roundtrip"),
+		func(input string) {
+			p := make.NewParser(bytes.NewBufferString(input), nil, parser.WithMode(parser.ParseRecipes|parser.ParseComments))
 
 			f, err := p.ParseFile()
 
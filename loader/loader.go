@@ -0,0 +1,160 @@
+// Package loader parses a Makefile along with every file it includes.
+package loader
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/unmango/go-make/ast"
+	"github.com/unmango/go-make/parser"
+	"github.com/unmango/go-make/scanner"
+	"github.com/unmango/go-make/token"
+)
+
+// A Package is a root Makefile and every file it includes,
+// parsed against a shared [token.FileSet].
+type Package struct {
+	Root     string               // path of the root Makefile
+	Fset     *token.FileSet       // positions for every file in the package
+	Files    map[string]*ast.File // parsed files keyed by path
+	Includes map[string][]string  // included paths keyed by the path of the including file
+}
+
+type loader struct {
+	fsys   fs.FS
+	pkg    *Package
+	errors scanner.ErrorList
+}
+
+// Load parses the Makefile at root in fsys and follows its include, -include,
+// and sinclude directives. Include paths are resolved relative to the root of
+// fsys, the same way make resolves them relative to its working directory.
+//
+// Directives are followed in every branch of a conditional, since conditions
+// are not evaluated. File names containing variable references cannot be
+// resolved without evaluation and are skipped. Missing files named by -include
+// and sinclude are ignored, while missing files named by include are reported
+// at the position of the file name.
+//
+// A partially loaded Package is returned alongside any errors.
+func Load(fsys fs.FS, root string) (*Package, error) {
+	root = path.Clean(root)
+	src, err := fs.ReadFile(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &loader{
+		fsys: fsys,
+		pkg: &Package{
+			Root:     root,
+			Fset:     token.NewFileSet(),
+			Files:    map[string]*ast.File{},
+			Includes: map[string][]string{},
+		},
+	}
+
+	l.load(root, src)
+	l.errors.Sort()
+	return l.pkg, l.errors.Err()
+}
+
+func (l *loader) load(name string, src []byte) {
+	file := l.pkg.Fset.AddFile(name, l.pkg.Fset.Base(), len(src))
+	f, err := parser.New(bytes.NewReader(src), file).ParseFile()
+	if err != nil {
+		// Keep following the includes of the partially parsed file
+		l.addError(token.Position{Filename: name}, err)
+	}
+
+	l.pkg.Files[name] = f
+	for _, d := range includes(f) {
+		for _, word := range words(d.Files) {
+			for _, p := range l.resolve(word) {
+				l.include(name, d, word, p)
+			}
+		}
+	}
+}
+
+func (l *loader) include(from string, d *ast.IncludeDir, word []ast.Expr, name string) {
+	if _, ok := l.pkg.Files[name]; ok {
+		l.pkg.Includes[from] = append(l.pkg.Includes[from], name)
+		return
+	}
+
+	src, err := fs.ReadFile(l.fsys, name)
+	if err != nil {
+		if d.Tok == token.INCLUDE || !errors.Is(err, fs.ErrNotExist) {
+			l.addError(l.pkg.Fset.Position(word[0].Pos()), err)
+		}
+		return
+	}
+
+	l.pkg.Includes[from] = append(l.pkg.Includes[from], name)
+	l.load(name, src)
+}
+
+// resolve returns the paths named by word, expanding wildcards the same way make does.
+func (l *loader) resolve(word []ast.Expr) []string {
+	b := &strings.Builder{}
+	for _, e := range word {
+		t, ok := e.(*ast.Text)
+		if !ok {
+			return nil
+		}
+		b.WriteString(t.Value)
+	}
+
+	name := path.Clean(b.String())
+	if strings.ContainsAny(name, "*?[") {
+		if matches, err := fs.Glob(l.fsys, name); err == nil && len(matches) > 0 {
+			return matches
+		}
+	}
+
+	return []string{name}
+}
+
+func (l *loader) addError(pos token.Position, err error) {
+	var list scanner.ErrorList
+	if errors.As(err, &list) {
+		l.errors = append(l.errors, list...)
+	} else {
+		l.errors.Add(pos, err.Error())
+	}
+}
+
+func includes(f *ast.File) (l []*ast.IncludeDir) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		if d, ok := n.(*ast.IncludeDir); ok {
+			l = append(l, d)
+		}
+		return true
+	})
+
+	return
+}
+
+// words groups adjacent expressions, i.e. `foo$(BAR).mk`, into the whitespace separated words make would see.
+// A continuation separates words the same as whitespace.
+func words(l []ast.Expr) (w [][]ast.Expr) {
+	var prev ast.Expr
+	for _, e := range l {
+		if _, ok := e.(*ast.Continuation); ok {
+			prev = nil
+			continue
+		}
+		if prev != nil && prev.End() == e.Pos() {
+			w[len(w)-1] = append(w[len(w)-1], e)
+		} else {
+			w = append(w, []ast.Expr{e})
+		}
+		prev = e
+	}
+
+	return
+}
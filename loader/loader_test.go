@@ -0,0 +1,195 @@
+package loader_test
+
+import (
+	"io/fs"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/unmango/go-make/ast"
+	"github.com/unmango/go-make/loader"
+	"github.com/unmango/go-make/token"
+)
+
+var _ = Describe("Loader", func() {
+	It("should load a Makefile without includes", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("target:\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkg.Root).To(Equal("Makefile"))
+		Expect(pkg.Files).To(HaveKey("Makefile"))
+		Expect(pkg.Includes).To(BeEmpty())
+	})
+
+	It("should load included files", func() {
+		fsys := fstest.MapFS{
+			"Makefile":     {Data: []byte("include mk/a.mk mk/b.mk\n")},
+			"mk/a.mk":      {Data: []byte("A := a\n")},
+			"mk/b.mk":      {Data: []byte("-include mk/nested.mk\n")},
+			"mk/nested.mk": {Data: []byte("nested:\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkg.Files).To(HaveLen(4))
+		Expect(pkg.Includes).To(Equal(map[string][]string{
+			"Makefile": {"mk/a.mk", "mk/b.mk"},
+			"mk/b.mk":  {"mk/nested.mk"},
+		}))
+	})
+
+	It("should load includes continued onto the next line", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("include a.mk\\\n\tb.mk\n")},
+			"a.mk":     {Data: []byte("A := a\n")},
+			"b.mk":     {Data: []byte("B := b\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkg.Includes).To(Equal(map[string][]string{
+			"Makefile": {"a.mk", "b.mk"},
+		}))
+	})
+
+	It("should parse every file against the shared file set", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("include a.mk\n")},
+			"a.mk":     {Data: []byte("A := a\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).NotTo(HaveOccurred())
+		v := pkg.Files["a.mk"].Contents[0].(*ast.Variable)
+		Expect(pkg.Fset.Position(v.Pos())).To(Equal(token.Position{
+			Filename: "a.mk",
+			Offset:   0,
+			Line:     1,
+			Column:   1,
+		}))
+	})
+
+	It("should expand wildcards", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("include mk/*.mk\n")},
+			"mk/a.mk":  {Data: []byte("A := a\n")},
+			"mk/b.mk":  {Data: []byte("B := b\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkg.Includes["Makefile"]).To(Equal([]string{"mk/a.mk", "mk/b.mk"}))
+	})
+
+	It("should follow includes in conditional directives", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("ifdef CI\ninclude ci.mk\nelse\ninclude local.mk\nendif\n")},
+			"ci.mk":    {Data: []byte("CI := true\n")},
+			"local.mk": {Data: []byte("CI :=\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkg.Includes["Makefile"]).To(Equal([]string{"ci.mk", "local.mk"}))
+	})
+
+	It("should load each file once", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("include a.mk\n")},
+			"a.mk":     {Data: []byte("include Makefile a.mk\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkg.Files).To(HaveLen(2))
+		Expect(pkg.Includes).To(Equal(map[string][]string{
+			"Makefile": {"a.mk"},
+			"a.mk":     {"Makefile", "a.mk"},
+		}))
+	})
+
+	It("should skip file names containing variable references", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("include $(DIR)/a.mk\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkg.Files).To(HaveLen(1))
+	})
+
+	DescribeTable("should ignore missing optional includes",
+		Entry(nil, "-include missing.mk\n"),
+		Entry(nil, "sinclude missing.mk\n"),
+		func(input string) {
+			fsys := fstest.MapFS{
+				"Makefile": {Data: []byte(input)},
+			}
+
+			pkg, err := loader.Load(fsys, "Makefile")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pkg.Files).To(HaveLen(1))
+			Expect(pkg.Includes).To(BeEmpty())
+		},
+	)
+
+	It("should error on a missing include", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("A := a\ninclude missing.mk\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).To(MatchError("Makefile:2:9: open missing.mk: file does not exist"))
+		Expect(pkg.Files).To(HaveKey("Makefile"))
+	})
+
+	It("should return parse errors from included files", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("include a.mk\n")},
+			"a.mk":     {Data: []byte("ifeq\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).To(MatchError(ContainSubstring("a.mk:1:5: expected one of '(', ''', '\"'")))
+		Expect(pkg.Files).To(HaveKey("a.mk"))
+		Expect(pkg.Includes).To(Equal(map[string][]string{
+			"Makefile": {"a.mk"},
+		}))
+	})
+
+	It("should follow includes after a parse error", func() {
+		fsys := fstest.MapFS{
+			"Makefile": {Data: []byte("target\ninclude a.mk\n")},
+			"a.mk":     {Data: []byte("A := a\n")},
+		}
+
+		pkg, err := loader.Load(fsys, "Makefile")
+
+		Expect(err).To(MatchError(ContainSubstring("Makefile:1:7: expected ':' or assignment")))
+		Expect(pkg.Files).To(HaveLen(2))
+		Expect(pkg.Includes).To(Equal(map[string][]string{
+			"Makefile": {"a.mk"},
+		}))
+	})
+
+	It("should error when the root does not exist", func() {
+		_, err := loader.Load(fstest.MapFS{}, "Makefile")
+
+		Expect(err).To(MatchError(fs.ErrNotExist))
+	})
+})
@@ -4,26 +4,67 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/unmango/go-make/ast"
 	"github.com/unmango/go-make/scanner"
 	"github.com/unmango/go-make/token"
+	"github.com/unmango/go/fopt"
 )
 
+// A Mode value is a set of flags (or 0). They control optional parser functionality.
+type Mode uint
+
+const (
+	ParseRecipes         Mode = 1 << iota // parse references in recipe text into ast.Recipe.Exprs
+	ParseComments                         // parse comments and add them to the AST
+	Trace                                 // print a trace of parsed productions
+	AllErrors                             // report all errors (not just the first 10 on different lines)
+	SkipObjectResolution                  // no effect; make has no declarations to resolve, the flag exists for parity with go/parser
+)
+
+type Op func(*Parser)
+
+// WithMode sets the mode flags of the parser.
+func WithMode(mode Mode) Op {
+	return func(p *Parser) {
+		p.mode = mode
+	}
+}
+
+// WithTraceOutput sets the writer used by the Trace mode. The default is os.Stdout.
+func WithTraceOutput(w io.Writer) Op {
+	return func(p *Parser) {
+		p.traceOut = w
+	}
+}
+
 type Parser struct {
 	s      *scanner.Scanner
+	outer  []*scanner.Scanner // scanners suspended by rescan
 	file   *token.File
+	mode   Mode
 	errors scanner.ErrorList
 
+	// Tracing/debugging
+	trace    bool      // == (mode&Trace != 0)
+	traceOut io.Writer // trace output
+	indent   int       // indentation used for tracing output
+
 	pos token.Pos
 	tok token.Token // one token look-ahead
 	lit string      // token literal
 
-	recipePrefix token.Token
+	recipePrefix string            // current .RECIPEPREFIX character
+	inRecipe     bool              // whether recipe lines continue the last rule
+	leadComment  *ast.CommentGroup // last comment group
+	tabs         []token.Pos       // positions of tabs skipped as whitespace
 }
 
-func New(r io.Reader, file *token.File) *Parser {
+func New(r io.Reader, file *token.File, opts ...Op) *Parser {
 	if file == nil {
 		file = token.NewFileSet().AddFile("", 1, math.MaxInt-2)
 	}
@@ -32,19 +73,85 @@ func New(r io.Reader, file *token.File) *Parser {
 		s:    scanner.New(r, file),
 		file: file,
 
-		recipePrefix: token.TAB,
+		traceOut: os.Stdout,
+
+		recipePrefix: "\t",
 	}
+	fopt.ApplyAll(p, opts)
+	p.trace = p.mode&Trace != 0
 	p.next()
 
 	return p
 }
 
+// ----------------------------------------------------------------------------
+// Parsing support
+
+func (p *Parser) printTrace(a ...any) {
+	const dots = ". . . . . . . . . . . . . . . . . . . . . . . . . . . . . . . . "
+	const n = len(dots)
+	pos := p.file.Position(p.pos)
+	fmt.Fprintf(p.traceOut, "%5d:%3d: ", pos.Line, pos.Column)
+	i := 2 * p.indent
+	for i > n {
+		fmt.Fprint(p.traceOut, dots)
+		i -= n
+	}
+	// i <= n
+	fmt.Fprint(p.traceOut, dots[0:i])
+	fmt.Fprintln(p.traceOut, a...)
+}
+
+func trace(p *Parser, msg string) *Parser {
+	p.printTrace(msg, "(")
+	p.indent++
+	return p
+}
+
+// Usage pattern: defer un(trace(p, "..."))
+func un(p *Parser) {
+	p.indent--
+	p.printTrace(")")
+}
+
 func (p Parser) isRecipePrefix() bool {
-	return p.tok == p.recipePrefix
+	if p.recipePrefix == "\t" {
+		return p.tok == token.TAB
+	}
+
+	return p.tok != token.EOF && strings.HasPrefix(p.recipeTokenText(), p.recipePrefix)
+}
+
+// setRecipePrefix updates the recipe prefix when v assigns .RECIPEPREFIX.
+// An empty value resets the prefix to a tab. [Special Variables]
+//
+// [Special Variables]: https://www.gnu.org/software/make/manual/html_node/Special-Variables.html
+func (p *Parser) setRecipePrefix(v *ast.Variable) {
+	if name, ok := v.Name.(*ast.Text); !ok || name.Value != ".RECIPEPREFIX" {
+		return
+	}
+	if len(v.Value) == 0 {
+		p.recipePrefix = "\t"
+	} else if text, ok := v.Value[0].(*ast.Text); ok {
+		p.recipePrefix = string([]rune(text.Value)[:1])
+	}
 }
 
 func (p *Parser) error(pos token.Pos, msg string) {
 	epos := p.file.Position(pos)
+
+	// If AllErrors is not set, discard errors reported on the same line
+	// as the last recorded error and stop parsing after 10 errors.
+	if p.mode&AllErrors == 0 {
+		n := len(p.errors)
+		if n > 0 && p.errors[n-1].Pos.Line == epos.Line {
+			return // discard - likely a spurious error
+		}
+		if n >= 10 {
+			panic(bailout{})
+		}
+	}
+
 	p.errors.Add(epos, msg)
 }
 
@@ -89,21 +196,86 @@ func (p *Parser) expectOneOf(tok ...token.Token) token.Pos {
 
 func (p *Parser) next() {
 	p.pos, p.tok, p.lit = p.s.Scan()
+	for p.tok == token.EOF && len(p.outer) > 0 {
+		n := len(p.outer) - 1
+		p.s, p.outer = p.outer[n], p.outer[:n]
+		p.pos, p.tok, p.lit = p.s.Scan()
+	}
+
+	if p.trace && p.pos.IsValid() {
+		s := strconv.Quote(p.tok.String())
+		if p.tok.IsLiteral() {
+			p.printTrace(s, strconv.Quote(p.lit))
+		} else {
+			p.printTrace(s)
+		}
+	}
+}
+
+// rescan replaces the current COMMENT token with the tokens of its text,
+// for contexts where make does not treat '#' as the start of a comment.
+func (p *Parser) rescan() {
+	pos := p.pos + 1 // pos + len('#')
+	file := token.NewFileSet().AddFile(p.file.Name(), int(pos), len(p.lit))
+	p.outer = append(p.outer, p.s)
+	p.s = scanner.New(strings.NewReader(p.lit), file)
+	p.next()
+}
+
+// advance consumes tokens up to the next line boundary,
+// resynchronizing the parser after a syntax error.
+func (p *Parser) advance() {
+	for p.tok != token.NEWLINE && p.tok != token.EOF {
+		p.next()
+	}
+}
+
+func (p *Parser) isAssign() bool {
+	switch p.tok {
+	case token.SIMPLE_ASSIGN, token.POSIX_ASSIGN, token.IMMEDIATE_ASSIGN,
+		token.IFNDEF_ASSIGN, token.RECURSIVE_ASSIGN, token.SHELL_ASSIGN,
+		token.APPEND_ASSIGN:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Parser) isModifier() bool {
+	switch p.tok {
+	case token.OVERRIDE, token.EXPORT, token.PRIVATE:
+		return true
+	default:
+		return false
+	}
 }
 
 func (p *Parser) isWhitespace() bool {
-	return p.tok == token.NEWLINE || p.tok == token.TAB
+	// A tab following a rule begins a recipe line
+	return p.tok == token.NEWLINE || p.tok == token.TAB && !(p.inRecipe && p.recipePrefix == "\t")
 }
 
 func (p *Parser) skipWhitespace() {
 	for p.tok != token.EOF && p.isWhitespace() {
+		if p.tok == token.TAB {
+			p.tabs = append(p.tabs, p.pos)
+		}
 		p.next()
 	}
 }
 
+func (p *Parser) isText() bool {
+	// Built-in function names only have special meaning inside a reference
+	return p.tok == token.TEXT || p.tok.IsBuiltinFunction()
+}
+
 func (p *Parser) parseText() *ast.Text {
+	if p.trace {
+		defer un(trace(p, "Text"))
+	}
+
 	pos, name := p.pos, "_"
-	if p.tok == token.TEXT {
+	if p.isText() {
 		name = p.lit
 		p.next()
 	} else {
@@ -116,66 +288,258 @@ func (p *Parser) parseText() *ast.Text {
 	}
 }
 
-func (p *Parser) parseRef() *ast.VarRef {
+func closing(open token.Token) token.Token {
+	if open == token.LBRACE {
+		return token.RBRACE
+	} else {
+		return token.RPAREN
+	}
+}
+
+func (p *Parser) parseRef() ast.Expr {
+	if p.trace {
+		defer un(trace(p, "Ref"))
+	}
+
 	if p.tok != token.DOLLAR {
-		p.expect(token.DOLLAR)
-		return nil
+		pos := p.expect(token.DOLLAR)
+		return &ast.BadExpr{From: pos, To: p.pos}
 	}
 
 	dollar := p.pos
 	p.next()
 
-	open, name := token.ILLEGAL, "_"
-	switch p.tok {
-	case token.LPAREN, token.LBRACE:
-		open = p.tok
+	var name []ast.Expr
+	switch {
+	case p.tok == token.DOLLAR && p.pos == dollar+1:
 		p.next()
-		if p.tok == token.TEXT {
-			name = p.lit
+		return &ast.Escape{Dollar: dollar}
+	case p.tok == token.LPAREN || p.tok == token.LBRACE:
+		open := p.tok
+		p.next()
+		if p.tok.IsBuiltinFunction() {
+			fn, fnPos := p.tok, p.pos
+			name = append(name, p.parseText())
+			if p.pos != name[0].End() || p.tok == token.TAB {
+				return p.parseFuncCall(dollar, open, fn, fnPos)
+			}
+		}
+
+		name = p.parseRefName(name)
+		if p.tok == token.COLON || p.tok == token.SIMPLE_ASSIGN {
+			return p.parseSubstRef(dollar, open, name)
+		}
+
+		close, closePos := token.ILLEGAL, token.NoPos
+		switch p.tok {
+		case token.RPAREN, token.RBRACE:
+			close, closePos = p.tok, p.pos
 			p.next()
-		} else {
-			p.expect(token.TEXT)
+		default:
+			p.expectOneOf(token.RPAREN, token.RBRACE)
+		}
+
+		return &ast.VarRef{
+			Dollar:   dollar,
+			Open:     open,
+			Name:     name,
+			Close:    close,
+			ClosePos: closePos,
 		}
-	case token.TEXT:
+	case p.isText():
 		if len(p.lit) == 1 {
-			name = p.lit
-			p.next()
+			name = append(name, p.parseText())
 		} else {
 			// TODO: This should occur in the scanner
-			name = p.lit[:1]
+			name = append(name, &ast.Text{Value: p.lit[:1], ValuePos: p.pos})
+			p.tok = token.TEXT
 			p.lit = p.lit[1:]
 			p.pos++
 		}
 	}
 
-	close := token.ILLEGAL
-	if open != token.ILLEGAL {
-		switch p.tok {
-		case token.RPAREN, token.RBRACE:
-			close = p.tok
+	return &ast.VarRef{
+		Dollar: dollar,
+		Open:   token.ILLEGAL,
+		Name:   name,
+		Close:  token.ILLEGAL,
+	}
+}
+
+// parseRefName parses the parts of a variable name following l,
+// stopping at the first token that is not adjacent to the name.
+func (p *Parser) parseRefName(l []ast.Expr) []ast.Expr {
+	for n := len(l); n == 0 || p.pos == l[n-1].End(); n = len(l) {
+		switch {
+		case p.isText():
+			l = append(l, p.parseText())
+		case p.tok == token.DOLLAR:
+			l = append(l, p.parseRef())
+		default:
+			return l
+		}
+	}
+
+	return l
+}
+
+func (p *Parser) parseSubstRef(dollar token.Pos, open token.Token, name []ast.Expr) *ast.SubstRef {
+	if p.trace {
+		defer un(trace(p, "SubstRef"))
+	}
+
+	colon, assign := p.pos, token.NoPos
+	if p.tok == token.SIMPLE_ASSIGN {
+		// The scanner reads an empty suffix, i.e. $(VAR:=.o), as ':='
+		assign = colon + 1
+	}
+	p.next()
+
+	var from, to []ast.Expr
+	for !assign.IsValid() && (p.isText() || p.tok == token.DOLLAR || p.tok == token.RECURSIVE_ASSIGN) {
+		switch {
+		case p.tok == token.RECURSIVE_ASSIGN:
+			assign = p.pos
+			p.next()
+		case p.isText() && strings.Contains(p.lit, "="):
+			// The scanner does not split '=' from the surrounding text
+			i := strings.Index(p.lit, "=")
+			assign = p.pos + token.Pos(i)
+			if lhs := p.lit[:i]; lhs != "" {
+				from = append(from, &ast.Text{Value: lhs, ValuePos: p.pos})
+			}
+			if rhs := p.lit[i+1:]; rhs != "" {
+				to = append(to, &ast.Text{Value: rhs, ValuePos: assign + 1})
+			}
 			p.next()
 		default:
+			from = append(from, p.parseExpression())
+		}
+	}
+	if !assign.IsValid() {
+		p.errorExpected(p.pos, "'='")
+	}
+
+	for p.isText() || p.tok == token.DOLLAR {
+		to = append(to, p.parseExpression())
+	}
+
+	closePos, close := p.pos, token.ILLEGAL
+	switch p.tok {
+	case token.RPAREN, token.RBRACE:
+		close = p.tok
+		p.next()
+	default:
+		if assign.IsValid() {
 			p.expectOneOf(token.RPAREN, token.RBRACE)
 		}
 	}
 
-	return &ast.VarRef{
-		Dollar: dollar,
-		Open:   open,
-		Name:   name,
-		Close:  close,
+	return &ast.SubstRef{
+		Dollar:   dollar,
+		Open:     open,
+		Name:     name,
+		Colon:    colon,
+		From:     from,
+		Assign:   assign,
+		To:       to,
+		Close:    close,
+		ClosePos: closePos,
+	}
+}
+
+func (p *Parser) parseFuncCall(dollar token.Pos, open, name token.Token, namePos token.Pos) *ast.FuncCall {
+	if p.trace {
+		defer un(trace(p, "FuncCall"))
+	}
+
+	close := closing(open)
+
+	var (
+		args   [][]ast.Expr
+		arg    []ast.Expr
+		commas []token.Pos
+	)
+
+	for depth := 0; p.tok != token.NEWLINE && p.tok != token.EOF; {
+		if p.tok == close && depth == 0 {
+			break
+		}
+
+		switch p.tok {
+		case token.DOLLAR:
+			arg = append(arg, p.parseRef())
+			continue
+		case token.COMMENT:
+			// '#' is literal inside a function call
+			arg = append(arg, &ast.Text{Value: "#", ValuePos: p.pos})
+			p.rescan()
+			continue
+		case token.CONTINUATION:
+			arg = append(arg, p.parseContinuation())
+			continue
+		case token.COMMA:
+			if depth == 0 {
+				args = append(args, arg)
+				commas = append(commas, p.pos)
+				arg = nil
+				p.next()
+				continue
+			}
+		case open:
+			depth++
+		case close:
+			depth--
+		}
+
+		// Everything else is plain text to the function
+		arg = append(arg, &ast.Text{
+			Value:    p.recipeTokenText(),
+			ValuePos: p.pos,
+		})
+		p.next()
 	}
+
+	args = append(args, arg)
+	closePos := p.expect(close)
+
+	return &ast.FuncCall{
+		Dollar:   dollar,
+		Open:     open,
+		Name:     name,
+		NamePos:  namePos,
+		Args:     args,
+		Commas:   commas,
+		Close:    close,
+		ClosePos: closePos,
+	}
+}
+
+func (p *Parser) parseContinuation() *ast.Continuation {
+	c := &ast.Continuation{
+		Backslash: p.pos,
+		Text:      p.lit,
+	}
+	p.next()
+
+	return c
 }
 
 func (p *Parser) parseExpression() ast.Expr {
-	switch p.tok {
-	case token.TEXT:
+	if p.trace {
+		defer un(trace(p, "Expression"))
+	}
+
+	switch {
+	case p.isText():
 		return p.parseText()
-	case token.DOLLAR:
+	case p.tok == token.DOLLAR:
 		return p.parseRef()
+	case p.tok == token.CONTINUATION:
+		return p.parseContinuation()
 	default:
-		p.expectOneOf(token.TEXT, token.DOLLAR)
-		return nil
+		pos := p.expectOneOf(token.TEXT, token.DOLLAR)
+		return &ast.BadExpr{From: pos, To: p.pos}
 	}
 }
 
@@ -192,7 +556,26 @@ func (p *Parser) parseComment() *ast.Comment {
 	}
 }
 
+// parseLineComment consumes the comment trailing the current line and returns
+// it if ParseComments is set, and nil otherwise.
+func (p *Parser) parseLineComment() *ast.Comment {
+	if p.tok != token.COMMENT {
+		return nil
+	}
+
+	c := p.parseComment()
+	if p.mode&ParseComments == 0 {
+		return nil
+	}
+
+	return c
+}
+
 func (p *Parser) parseCommentGroup() *ast.CommentGroup {
+	if p.trace {
+		defer un(trace(p, "CommentGroup"))
+	}
+
 	g := &ast.CommentGroup{}
 	for p.tok == token.COMMENT {
 		g.List = append(g.List, p.parseComment())
@@ -204,7 +587,22 @@ func (p *Parser) parseCommentGroup() *ast.CommentGroup {
 	return g
 }
 
+// docComment returns the last comment group if it ends on the line
+// before the current token, and nil otherwise.
+func (p *Parser) docComment() (doc *ast.CommentGroup) {
+	if g := p.leadComment; g != nil && p.file.Line(g.End())+1 == p.file.Line(p.pos) {
+		doc = g
+	}
+
+	p.leadComment = nil
+	return
+}
+
 func (p *Parser) parseIfdefDir() *ast.IfdefDir {
+	if p.trace {
+		defer un(trace(p, "IfdefDir"))
+	}
+
 	pos, tok := p.pos, p.tok
 	p.next()
 	arg := p.parseExpression()
@@ -213,10 +611,15 @@ func (p *Parser) parseIfdefDir() *ast.IfdefDir {
 		Tok:     tok,
 		TokPos:  pos,
 		VarName: arg,
+		Comment: p.parseLineComment(),
 	}
 }
 
 func (p *Parser) parseQuotedExpr() *ast.QuotedExpr {
+	if p.trace {
+		defer un(trace(p, "QuotedExpr"))
+	}
+
 	var (
 		quote token.Token
 		open  token.Pos
@@ -233,7 +636,7 @@ func (p *Parser) parseQuotedExpr() *ast.QuotedExpr {
 		p.expectOneOf(token.APOS, token.QUOTE)
 	}
 
-	value := p.parseText()
+	value := p.parseIfeqArg(quote)
 	close := p.expect(quote)
 
 	return &ast.QuotedExpr{
@@ -244,38 +647,77 @@ func (p *Parser) parseQuotedExpr() *ast.QuotedExpr {
 	}
 }
 
+// parseIfeqArg parses a conditional argument up to the first occurrence of end,
+// which is not consumed. Parentheses are balanced unless end is a quote.
+func (p *Parser) parseIfeqArg(end token.Token) (l []ast.Expr) {
+	if p.trace {
+		defer un(trace(p, "IfeqArg"))
+	}
+
+	quoted := end == token.APOS || end == token.QUOTE
+	for depth := 0; p.tok != token.NEWLINE && p.tok != token.EOF && p.tok != token.COMMENT; {
+		if p.tok == end && (depth == 0 || quoted) {
+			break
+		}
+
+		switch {
+		case p.isText(), p.tok == token.DOLLAR, p.tok == token.CONTINUATION:
+			l = append(l, p.parseExpression())
+			continue
+		case p.tok == token.LPAREN && !quoted:
+			depth++
+		case p.tok == token.RPAREN && !quoted:
+			depth--
+		}
+
+		// Everything else is plain text to the condition
+		l = append(l, &ast.Text{
+			Value:    p.recipeTokenText(),
+			ValuePos: p.pos,
+		})
+		p.next()
+	}
+
+	return
+}
+
 func (p *Parser) parseIfeqDir() *ast.IfeqDir {
+	if p.trace {
+		defer un(trace(p, "IfeqDir"))
+	}
+
 	pos, tok := p.pos, p.tok
 	p.next() // consume ifeq or ifneq
 
 	var (
 		lparen, rparen token.Pos
-		arg1, arg2     ast.Expr
+		arg1, arg2     []ast.Expr
 		comma          token.Pos
 	)
 
 	switch p.tok {
 	case token.LPAREN:
 		lparen = p.expect(token.LPAREN)
-		arg1 = p.parseExpression()
+		arg1 = p.parseIfeqArg(token.COMMA)
 		comma = p.expect(token.COMMA)
-		arg2 = p.parseExpression()
+		arg2 = p.parseIfeqArg(token.RPAREN)
 		rparen = p.expect(token.RPAREN)
 	case token.APOS, token.QUOTE:
-		arg1 = p.parseQuotedExpr()
-		arg2 = p.parseQuotedExpr()
+		arg1 = []ast.Expr{p.parseQuotedExpr()}
+		arg2 = []ast.Expr{p.parseQuotedExpr()}
 	default:
 		p.expectOneOf(token.LPAREN, token.APOS, token.QUOTE)
 	}
 
 	return &ast.IfeqDir{
-		Tok:    tok,
-		TokPos: pos,
-		Open:   lparen,
-		Arg1:   arg1,
-		Comma:  comma,
-		Arg2:   arg2,
-		Close:  rparen,
+		Tok:     tok,
+		TokPos:  pos,
+		Open:    lparen,
+		Arg1:    arg1,
+		Comma:   comma,
+		Arg2:    arg2,
+		Close:   rparen,
+		Comment: p.parseLineComment(),
 	}
 }
 
@@ -291,20 +733,34 @@ func (p *Parser) parseIfDir() (d ast.IfDir) {
 }
 
 func (p *Parser) parseElseBlock() *ast.ElseBlock {
+	if p.trace {
+		defer un(trace(p, "ElseBlock"))
+	}
+
 	pos := p.expect(token.ELSE)
 	condition := p.parseIfDir()
 
+	var comment *ast.Comment
+	if condition == nil {
+		comment = p.parseLineComment()
+	}
+
 	p.skipWhitespace()
 	text := p.parseObjList()
 
 	return &ast.ElseBlock{
 		Else:      pos,
 		Condition: condition,
+		Comment:   comment,
 		Text:      text,
 	}
 }
 
 func (p *Parser) parseIfBlock() *ast.IfBlock {
+	if p.trace {
+		defer un(trace(p, "IfBlock"))
+	}
+
 	ifdir := p.parseIfDir()
 	p.skipWhitespace()
 	text := p.parseObjList()
@@ -323,74 +779,366 @@ func (p *Parser) parseIfBlock() *ast.IfBlock {
 	endif := p.expect(token.ENDIF)
 
 	return &ast.IfBlock{
-		Directive: ifdir,
-		Text:      text,
-		Else:      eblocks,
-		Endif:     endif,
+		Directive:    ifdir,
+		Text:         text,
+		Else:         eblocks,
+		Endif:        endif,
+		EndifComment: p.parseLineComment(),
 	}
 }
 
+func (p *Parser) parseDefineDir() *ast.DefineDir {
+	if p.trace {
+		defer un(trace(p, "DefineDir"))
+	}
+
+	define := p.expect(token.DEFINE)
+	name := p.parseExpression()
+
+	op, opPos := token.ILLEGAL, token.NoPos
+	if p.isAssign() {
+		op, opPos = p.tok, p.pos
+		p.next()
+	}
+
+	var body []*ast.Text
+	for depth := 0; p.tok == token.NEWLINE; {
+		start := p.pos + 1
+		p.next()
+
+		if p.tok == token.ENDEF {
+			if depth == 0 {
+				break
+			}
+			depth-- // endef of a nested define
+		} else if p.tok == token.DEFINE {
+			depth++
+		}
+
+		body = append(body, &ast.Text{
+			Value:    p.parseLineText(start),
+			ValuePos: start,
+		})
+	}
+
+	endef := p.expect(token.ENDEF)
+
+	return &ast.DefineDir{
+		Define:  define,
+		Name:    name,
+		Op:      op,
+		OpPos:   opPos,
+		Body:    body,
+		Endef:   endef,
+		Comment: p.parseLineComment(),
+	}
+}
+
+func (p *Parser) parseIncludeDir() *ast.IncludeDir {
+	if p.trace {
+		defer un(trace(p, "IncludeDir"))
+	}
+
+	pos, tok := p.pos, p.tok
+	p.next() // consume include, -include, or sinclude
+
+	var files []ast.Expr
+	for p.tok != token.NEWLINE && p.tok != token.EOF && p.tok != token.COMMENT {
+		files = append(files, p.parseExpression())
+	}
+
+	return &ast.IncludeDir{
+		Tok:     tok,
+		TokPos:  pos,
+		Files:   files,
+		Comment: p.parseLineComment(),
+	}
+}
+
+func (p *Parser) parseVpathDir() *ast.VpathDir {
+	if p.trace {
+		defer un(trace(p, "VpathDir"))
+	}
+
+	vpath := p.expect(token.VPATH)
+
+	var pattern ast.Expr
+	if p.tok != token.NEWLINE && p.tok != token.EOF && p.tok != token.COMMENT {
+		pattern = p.parseExpression()
+	}
+
+	var (
+		dirs   []ast.Expr
+		colons []token.Pos
+	)
+	for p.tok != token.NEWLINE && p.tok != token.EOF && p.tok != token.COMMENT {
+		switch {
+		case p.tok == token.COLON:
+			colons = append(colons, p.pos)
+			p.next()
+		case p.isText(), p.tok == token.DOLLAR, p.tok == token.CONTINUATION:
+			dirs = append(dirs, p.parseExpression())
+		default:
+			// Keywords have no special meaning in a directory list, i.e. vpath % include
+			dirs = append(dirs, &ast.Text{
+				Value:    p.recipeTokenText(),
+				ValuePos: p.pos,
+			})
+			p.next()
+		}
+	}
+
+	return &ast.VpathDir{
+		Vpath:       vpath,
+		Pattern:     pattern,
+		Directories: dirs,
+		Colons:      colons,
+		Comment:     p.parseLineComment(),
+	}
+}
+
+// parseObj parses the next object. A comment group is dropped,
+// and the result is nil, unless ParseComments is set.
 func (p *Parser) parseObj() ast.Obj {
+	if p.trace {
+		defer un(trace(p, "Obj"))
+	}
+
+	doc := p.docComment()
+	if p.inRecipe && p.isRecipePrefix() {
+		return p.parseRecipe()
+	}
+
+	// Comments and conditional directives do not end the recipes of a rule
 	switch p.tok {
 	case token.COMMENT:
-		return p.parseCommentGroup()
+		g := p.parseCommentGroup()
+		if p.mode&ParseComments == 0 {
+			return nil
+		}
+
+		p.leadComment = g
+		return g
 	case token.IFDEF, token.IFNDEF, token.IFEQ, token.IFNEQ:
 		return p.parseIfBlock()
 	}
 
+	p.inRecipe = false
+	switch p.tok {
+	case token.DEFINE:
+		return p.parseDefineDir()
+	case token.INCLUDE, token.DASH_INCLUDE, token.SINCLUDE:
+		return p.parseIncludeDir()
+	case token.OVERRIDE, token.EXPORT, token.UNEXPORT, token.PRIVATE, token.UNDEFINE:
+		return p.parseModifiedObj(doc)
+	case token.VPATH:
+		return p.parseVpathDir()
+	}
+
 	// TODO: refactor to improve the error message
 	// we expect one expression, then we expect one
 	// of (Expr | COLON | *_ASSIGN)
 	var l []ast.Expr
-	for p.tok == token.TEXT || p.tok == token.DOLLAR {
+	for p.isText() || p.tok == token.DOLLAR || p.tok == token.CONTINUATION {
 		l = append(l, p.parseExpression())
 	}
 
-	switch p.tok {
-	case token.COLON:
-		return p.parseRule(l)
-	case token.SIMPLE_ASSIGN, token.POSIX_ASSIGN, token.IMMEDIATE_ASSIGN,
-		token.IFNDEF_ASSIGN, token.RECURSIVE_ASSIGN, token.SHELL_ASSIGN:
+	switch {
+	case p.tok == token.COLON || p.tok == token.DOUBLE_COLON || p.tok == token.AND_COLON:
+		if len(l) > 0 {
+			return p.parseRule(doc, l)
+		}
+		p.errorExpected(p.pos, "target")
+	case p.isAssign():
 		if len(l) == 1 {
-			return p.parseVar(l[0])
+			v := p.parseVar(doc, l[0])
+			p.setRecipePrefix(v)
+			return v
 		}
 		p.error(p.pos, "variable may have only one name")
-		fallthrough
 	default:
-		p.next()   // always progress
-		return nil // TODO: BadObj?
+		p.errorExpected(p.pos, "':' or assignment")
+	}
+
+	from := p.pos
+	if len(l) > 0 {
+		from = l[0].Pos()
+	}
+
+	p.advance()
+	return &ast.BadObj{From: from, To: p.pos}
+}
+
+// parseModifiedObj parses a variable assignment or define directive preceded by
+// modifiers, i.e. `export FOO := bar`, or one of the export, unexport, or undefine directives.
+func (p *Parser) parseModifiedObj(doc *ast.CommentGroup) ast.Obj {
+	if p.trace {
+		defer un(trace(p, "ModifiedObj"))
+	}
+
+	var mods []*ast.Modifier
+	for p.tok == token.UNEXPORT || p.isModifier() {
+		mods = append(mods, &ast.Modifier{Tok: p.tok, TokPos: p.pos})
+		p.next()
+	}
+
+	switch p.tok {
+	case token.UNDEFINE:
+		return p.parseUndefineDir(mods)
+	case token.DEFINE:
+		d := p.parseDefineDir()
+		d.Modifiers = mods
+		return d
+	}
+
+	var l []ast.Expr
+	for p.isText() || p.tok == token.DOLLAR || p.tok == token.CONTINUATION {
+		l = append(l, p.parseExpression())
+	}
+
+	switch {
+	case p.isAssign() && len(l) == 1:
+		v := p.parseVar(doc, l[0])
+		v.Modifiers = mods
+		p.setRecipePrefix(v)
+		return v
+	case p.isAssign():
+		p.error(p.pos, "variable may have only one name")
+	case p.tok != token.NEWLINE && p.tok != token.EOF && p.tok != token.COMMENT:
+		p.errorExpected(p.pos, "assignment operator")
+	case len(mods) == 1 && mods[0].Tok == token.EXPORT:
+		return &ast.ExportDir{
+			Export:  mods[0].TokPos,
+			Names:   l,
+			Comment: p.parseLineComment(),
+		}
+	case len(mods) == 1 && mods[0].Tok == token.UNEXPORT:
+		return &ast.UnexportDir{
+			Unexport: mods[0].TokPos,
+			Names:    l,
+			Comment:  p.parseLineComment(),
+		}
+	default:
+		p.errorExpected(p.pos, "assignment operator")
+	}
+
+	p.advance()
+	return &ast.BadObj{From: mods[0].Pos(), To: p.pos}
+}
+
+func (p *Parser) parseUndefineDir(mods []*ast.Modifier) *ast.UndefineDir {
+	if p.trace {
+		defer un(trace(p, "UndefineDir"))
+	}
+
+	undefine := p.expect(token.UNDEFINE)
+
+	return &ast.UndefineDir{
+		Modifiers: mods,
+		Undefine:  undefine,
+		Name:      p.parseExpression(),
+		Comment:   p.parseLineComment(),
 	}
 }
 
-func (p *Parser) parseObjList() (l []ast.Obj) {
+func (p *Parser) parseObjList() []ast.Obj {
+	l := &objList{}
 	for p.tok != token.EOF && p.tok != token.ENDIF && p.tok != token.ELSE {
-		l = append(l, p.parseObj())
+		l.add(p.parseObj())
 		p.skipWhitespace()
 	}
 
-	return
+	return l.objs()
+}
+
+// An objList collects parsed objects. Recipe lines, and conditional directives
+// containing recipe lines, are appended to the recipes of the rule they follow.
+// Comment groups between the recipes of a rule are kept with them, while comment
+// groups after its last recipe remain in the list.
+type objList struct {
+	list     []ast.Obj
+	rule     *ast.Rule // rule whose recipes may continue
+	comments []ast.Obj // comment groups following the last recipe of rule
+}
+
+func (l *objList) add(o ast.Obj) {
+	if o == nil {
+		return // comments are dropped unless ParseComments is set
+	}
+	if _, ok := o.(*ast.CommentGroup); ok && l.rule != nil {
+		l.comments = append(l.comments, o)
+	} else if l.rule != nil && isRecipeObj(o) {
+		l.rule.Recipes = append(l.rule.Recipes, l.comments...)
+		l.rule.Recipes = append(l.rule.Recipes, o)
+		l.comments = nil
+	} else {
+		l.list = append(l.list, l.comments...)
+		l.list = append(l.list, o)
+		l.comments = nil
+		l.rule, _ = o.(*ast.Rule)
+	}
 }
 
-func (p *Parser) parseVar(name ast.Expr) ast.Obj {
+func (l *objList) objs() []ast.Obj {
+	return append(l.list, l.comments...)
+}
+
+// isRecipeObj reports whether o is a recipe line or
+// a conditional directive containing recipe lines.
+func isRecipeObj(o ast.Obj) bool {
+	switch n := o.(type) {
+	case *ast.Recipe:
+		return true
+	case *ast.IfBlock:
+		if slices.ContainsFunc(n.Text, isRecipeObj) {
+			return true
+		}
+		for _, b := range n.Else {
+			if slices.ContainsFunc(b.Text, isRecipeObj) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *Parser) parseVar(doc *ast.CommentGroup, name ast.Expr) *ast.Variable {
+	if p.trace {
+		defer un(trace(p, "Variable"))
+	}
+
 	op, opPos := p.tok, p.pos
 	p.next()
 
 	var rhs []ast.Expr
-	for p.tok != token.NEWLINE && p.tok != token.EOF {
-		rhs = append(rhs, p.parseExpression())
+	for p.tok != token.NEWLINE && p.tok != token.EOF && p.tok != token.COMMENT {
+		switch {
+		case p.isText(), p.tok == token.DOLLAR, p.tok == token.CONTINUATION:
+			rhs = append(rhs, p.parseExpression())
+		default:
+			// Operators have no special meaning in a variable value, i.e. .RECIPEPREFIX = |
+			rhs = append(rhs, &ast.Text{
+				Value:    p.recipeTokenText(),
+				ValuePos: p.pos,
+			})
+			p.next()
+		}
 	}
 
 	return &ast.Variable{
-		Name:  name,
-		Op:    op,
-		OpPos: opPos,
-		Value: rhs,
+		Doc:     doc,
+		Name:    name,
+		Op:      op,
+		OpPos:   opPos,
+		Value:   rhs,
+		Comment: p.parseLineComment(),
 	}
 }
 
 func (p *Parser) recipeTokenText() string {
 	switch p.tok {
-	case token.TEXT:
+	case token.TEXT, token.CONTINUATION:
 		return p.lit
 	case token.COMMENT:
 		return "#" + p.lit
@@ -399,12 +1147,11 @@ func (p *Parser) recipeTokenText() string {
 	}
 }
 
-func (p *Parser) parseRecipe() *ast.Recipe {
-	prefixPos := p.expect(p.recipePrefix)
-	prefixText := p.recipePrefix.String()
-	prefixWidth := token.Pos(len(prefixText))
+// parseLineText consumes the remainder of the current line and returns it as raw
+// text starting at pos. Whitespace dropped by the scanner is restored from positions.
+func (p *Parser) parseLineText(pos token.Pos) string {
 	b := &strings.Builder{}
-	nextPos := prefixPos + prefixWidth
+	nextPos := pos
 	for p.tok != token.NEWLINE && p.tok != token.EOF {
 		if gap := int(p.pos - nextPos); gap > 0 {
 			for range gap {
@@ -417,24 +1164,226 @@ func (p *Parser) parseRecipe() *ast.Recipe {
 		nextPos = p.pos + token.Pos(len(text))
 		p.next()
 	}
+
+	return b.String()
+}
+
+// parseRecipeExprs consumes the remainder of the current line as a list of expressions
+// starting at pos. Text between references, including whitespace, is kept verbatim.
+func (p *Parser) parseRecipeExprs(pos token.Pos) (l []ast.Expr) {
+	text := &ast.Text{ValuePos: pos}
+	for p.tok != token.NEWLINE && p.tok != token.EOF {
+		if gap := int(p.pos - text.End()); gap > 0 {
+			text.Value += strings.Repeat(" ", gap)
+		}
+		if p.tok == token.DOLLAR {
+			if text.Value != "" {
+				l = append(l, text)
+			}
+
+			ref := p.parseRef()
+			l = append(l, ref)
+			text = &ast.Text{ValuePos: ref.End()}
+			continue
+		}
+		if p.tok == token.COMMENT {
+			// Text in the comment directly follows the text before it
+			text.Value += "#"
+			for _, e := range p.parseCommentExprs() {
+				if t, ok := e.(*ast.Text); ok {
+					text.Value += t.Value
+					continue
+				}
+				if text.Value != "" {
+					l = append(l, text)
+				}
+
+				l = append(l, e)
+				text = &ast.Text{ValuePos: e.End()}
+			}
+			p.next()
+			continue
+		}
+
+		text.Value += p.recipeTokenText()
+		p.next()
+	}
+	if text.Value != "" {
+		l = append(l, text)
+	}
+
+	return
+}
+
+// parseCommentExprs parses the text of the current COMMENT token as recipe expressions.
+// The scanner reads the rest of the line after '#' as a comment, but make expands
+// references in recipe text before the shell sees it. If the text can not be parsed,
+// parseCommentExprs returns the text as a single expression.
+func (p *Parser) parseCommentExprs() []ast.Expr {
+	pos := p.pos + 1 // pos + len('#')
+	file := token.NewFileSet().AddFile(p.file.Name(), int(pos), len(p.lit))
+	s := New(strings.NewReader(p.lit), file, WithMode(p.mode&^Trace))
+
+	exprs := s.parseRecipeExprs(pos)
+	if s.errors.Len() > 0 || s.tok != token.EOF {
+		return []ast.Expr{&ast.Text{Value: p.lit, ValuePos: pos}}
+	}
+
+	return exprs
+}
+
+// exprText returns the source text of l.
+func exprText(l []ast.Expr) string {
+	b := &strings.Builder{}
+	for _, e := range l {
+		fmt.Fprint(b, e)
+	}
+
+	return b.String()
+}
+
+// recipeModifiers returns the bounds of the leading '@', '-' and '+' modifiers
+// in text, including any whitespace between them but not before them.
+func recipeModifiers(text string) (start, end int) {
+	start = len(text) - len(strings.TrimLeft(text, " \t"))
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '@', '-', '+':
+			end = i + 1
+		case ' ', '\t':
+		default:
+			return
+		}
+	}
+
+	return
+}
+
+// skipRecipePrefix consumes a custom recipe prefix from the start of the current token.
+func (p *Parser) skipRecipePrefix() {
+	text := p.recipeTokenText()
+	if len(text) == len(p.recipePrefix) {
+		p.next()
+		return
+	}
+
+	// TODO: This should occur in the scanner
+	p.tok = token.TEXT
+	p.lit = text[len(p.recipePrefix):]
+	p.pos += token.Pos(len(p.recipePrefix))
+}
+
+func (p *Parser) parseRecipe() *ast.Recipe {
+	if p.trace {
+		defer un(trace(p, "Recipe"))
+	}
+
+	r := &ast.Recipe{Prefix: token.TAB, PrefixPos: p.pos}
+	prefixWidth := token.Pos(len(p.recipePrefix))
+	switch {
+	case p.tok == token.SEMI:
+		// An inline recipe on the rule line, i.e. target: prereq ; recipe
+		r.Prefix, prefixWidth = token.SEMI, 1
+		p.next()
+	case p.recipePrefix == "\t":
+		p.expect(token.TAB)
+	default:
+		r.Prefix, r.PrefixLit = token.TEXT, p.recipePrefix
+		p.skipRecipePrefix()
+	}
+
+	pos := r.PrefixPos + prefixWidth
+	var text string
+	if p.mode&ParseRecipes != 0 {
+		r.Exprs = p.parseRecipeExprs(pos)
+		text = exprText(r.Exprs)
+	} else {
+		text = p.parseLineText(pos)
+	}
+
+	if i, n := recipeModifiers(text); n > 0 {
+		r.Modifiers, r.ModifiersPos = text[i:n], pos+token.Pos(i)
+		text, pos = text[n:], pos+token.Pos(n)
+		if len(r.Exprs) > 0 {
+			// Modifiers are plain text, so they can only be in the first expression
+			t := r.Exprs[0].(*ast.Text)
+			t.Value, t.ValuePos = t.Value[n:], pos
+			if t.Value == "" {
+				r.Exprs = r.Exprs[1:]
+			}
+		}
+	}
+
+	r.Value, r.ValuePos = text, pos
 	if p.tok == token.NEWLINE {
 		p.next()
 	}
 
-	return &ast.Recipe{
-		Prefix:    p.recipePrefix,
-		PrefixPos: prefixPos,
-		Text: ast.Text{
-			Value:    b.String(),
-			ValuePos: prefixPos + prefixWidth,
-		},
+	return r
+}
+
+func (p *Parser) parseTargetVar(targets []ast.Expr, tok token.Token, colon token.Pos) *ast.TargetVariable {
+	if p.trace {
+		defer un(trace(p, "TargetVariable"))
+	}
+
+	var mods []*ast.Modifier
+	for p.isModifier() {
+		mods = append(mods, &ast.Modifier{Tok: p.tok, TokPos: p.pos})
+		p.next()
+	}
+
+	name := p.parseExpression()
+	if !p.isAssign() {
+		p.errorExpected(p.pos, "assignment operator")
+	}
+
+	v := p.parseVar(nil, name)
+	v.Modifiers = mods
+
+	return &ast.TargetVariable{
+		Targets:  targets,
+		Tok:      tok,
+		Colon:    colon,
+		Variable: v,
 	}
 }
 
-func (p *Parser) parseRule(targets []ast.Expr) *ast.Rule {
-	colon := p.expect(token.COLON)
+func (p *Parser) parseRule(doc *ast.CommentGroup, targets []ast.Expr) ast.Obj {
+	if p.trace {
+		defer un(trace(p, "Rule"))
+	}
+
+	tok, colon := p.tok, p.pos
+	p.next() // consume ':', '::', or '&:'
+
+	if p.isModifier() {
+		return p.parseTargetVar(targets, tok, colon)
+	}
+
+	var (
+		pattern      []ast.Expr
+		patternColon token.Pos
+	)
+
 	prereqs := []ast.Expr{}
-	for p.tok != token.PIPE && p.tok != token.NEWLINE && p.tok != token.EOF {
+	for p.tok != token.PIPE && p.tok != token.SEMI && p.tok != token.COMMENT && p.tok != token.NEWLINE && p.tok != token.EOF {
+		if p.tok == token.COLON && !patternColon.IsValid() {
+			// Everything so far was the target-pattern of a static pattern rule
+			pattern, patternColon = prereqs, p.pos
+			prereqs = []ast.Expr{}
+			p.next()
+			continue
+		}
+		if p.isAssign() && len(prereqs) == 1 && !patternColon.IsValid() {
+			return &ast.TargetVariable{
+				Targets:  targets,
+				Tok:      tok,
+				Colon:    colon,
+				Variable: p.parseVar(nil, prereqs[0]),
+			}
+		}
+
 		prereqs = append(prereqs, p.parseExpression())
 	}
 
@@ -442,49 +1391,197 @@ func (p *Parser) parseRule(targets []ast.Expr) *ast.Rule {
 	if p.tok == token.PIPE {
 		pipe = p.pos
 		p.next()
-		for p.tok != token.NEWLINE && p.tok != token.EOF {
+		for p.tok != token.SEMI && p.tok != token.COMMENT && p.tok != token.NEWLINE && p.tok != token.EOF {
 			oprereqs = append(oprereqs, p.parseExpression())
 		}
 	}
-	if p.tok == token.NEWLINE {
+	comment := p.parseLineComment()
+
+	recipes := make([]ast.Obj, 0)
+	if p.tok == token.SEMI {
+		recipes = append(recipes, p.parseRecipe())
+	} else if p.tok == token.NEWLINE {
 		p.next()
 	}
-
-	recipes := make([]*ast.Recipe, 0)
 	for p.isRecipePrefix() && p.tok != token.EOF {
 		recipes = append(recipes, p.parseRecipe())
 	}
+	p.inRecipe = true
 
 	return &ast.Rule{
+		Doc:          doc,
 		Targets:      targets,
+		Tok:          tok,
 		Colon:        colon,
+		Pattern:      pattern,
+		PatternColon: patternColon,
 		PreReqs:      prereqs,
 		Pipe:         pipe,
 		OrderPreReqs: oprereqs,
+		Comment:      comment,
 		Recipes:      recipes,
 	}
 }
 
 func (p *Parser) parseFile() *ast.File {
-	var content []ast.Obj
-	for p.tok != token.EOF {
-		p.skipWhitespace()
-		content = append(content, p.parseObj())
+	if p.trace {
+		defer un(trace(p, "File"))
+	}
+
+	content := &objList{}
+	for p.skipWhitespace(); p.tok != token.EOF; p.skipWhitespace() {
+		content.add(p.parseObj())
 	}
 
 	return &ast.File{
-		Contents:  content,
+		Contents:  content.objs(),
+		Tabs:      p.tabs,
 		FileStart: token.Pos(p.file.Base()),
 		FileEnd:   token.Pos(p.file.Base() + p.file.Size()),
 	}
 }
 
-func (p *Parser) ParseFile() (*ast.File, error) {
-	f := p.parseFile()
-	if p.errors.Len() > 0 {
+// A bailout panic is raised to indicate early termination.
+type bailout struct{}
+
+// ParseFile parses the source and returns the corresponding ast.File node.
+// If the source contains syntax errors, the result is a partial AST with
+// ast.BadObj and ast.BadExpr nodes in place of the erroneous source and
+// the error is a scanner.ErrorList sorted by source position.
+// Unless the AllErrors mode is set, parsing stops after the first 10 errors.
+func (p *Parser) ParseFile() (f *ast.File, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			// resume same panic if it's not a bailout
+			if _, ok := e.(bailout); !ok {
+				panic(e)
+			}
+		}
+
+		if f == nil {
+			f = &ast.File{
+				FileStart: token.Pos(p.file.Base()),
+				FileEnd:   token.Pos(p.file.Base() + p.file.Size()),
+			}
+		}
+
 		p.errors.Sort()
-		return nil, p.errors.Err()
-	} else {
-		return f, nil
+		err = p.errors.Err()
+	}()
+
+	return p.parseFile(), nil
+}
+
+// ParseExprFrom is a convenience function for parsing a single expression.
+// The arguments have the same meaning as for New, with the source src
+// added to fset as a file with the given filename. It is an error if src
+// contains input other than trailing whitespace after the expression.
+//
+// A word such as `$(OBJDIR)/foo.o` consists of several adjacent expressions;
+// use ParseExprListFrom to parse it.
+//
+// If the source contains syntax errors, the result is a partial AST
+// (possibly nil) and the error is a scanner.ErrorList sorted by source position.
+func ParseExprFrom(fset *token.FileSet, filename, src string, mode Mode) (expr ast.Expr, err error) {
+	p := New(strings.NewReader(src), fset.AddFile(filename, -1, len(src)), WithMode(mode))
+	defer func() {
+		if e := recover(); e != nil {
+			// resume same panic if it's not a bailout
+			if _, ok := e.(bailout); !ok {
+				panic(e)
+			}
+		}
+
+		p.errors.Sort()
+		err = p.errors.Err()
+	}()
+
+	expr = p.parseExpression()
+	p.skipWhitespace()
+	p.expect(token.EOF)
+
+	return
+}
+
+// ParseExpr is a convenience function for obtaining the AST of an expression x.
+// Positions in the AST are relative to a new token.FileSet, so the first byte
+// of the source is at token.Pos(1). The filename used in error messages is the
+// empty string.
+func ParseExpr(x string) (ast.Expr, error) {
+	return ParseExprFrom(token.NewFileSet(), "", x, 0)
+}
+
+// ParseExprListFrom is a convenience function for parsing a list of
+// expressions, such as the prerequisites of a rule or the value of a
+// variable. Adjacent expressions without whitespace between them form a
+// single word, i.e. `lib$(X).a`. The arguments have the same meaning as for
+// ParseExprFrom. It is an error if src contains more than one line.
+//
+// If the source contains syntax errors, the result is a partial AST
+// (possibly nil) and the error is a scanner.ErrorList sorted by source position.
+func ParseExprListFrom(fset *token.FileSet, filename, src string, mode Mode) (list []ast.Expr, err error) {
+	p := New(strings.NewReader(src), fset.AddFile(filename, -1, len(src)), WithMode(mode))
+	defer func() {
+		if e := recover(); e != nil {
+			// resume same panic if it's not a bailout
+			if _, ok := e.(bailout); !ok {
+				panic(e)
+			}
+		}
+
+		p.errors.Sort()
+		err = p.errors.Err()
+	}()
+
+	for p.tok != token.NEWLINE && p.tok != token.EOF {
+		list = append(list, p.parseExpression())
 	}
+	p.skipWhitespace()
+	p.expect(token.EOF)
+
+	return
+}
+
+// ParseExprList is a convenience function for obtaining the AST of a list of
+// expressions x. Positions and error messages are as for ParseExpr.
+func ParseExprList(x string) ([]ast.Expr, error) {
+	return ParseExprListFrom(token.NewFileSet(), "", x, 0)
+}
+
+// ParseObjFrom is a convenience function for parsing a single object, i.e.
+// a rule, variable, or directive. The arguments have the same meaning as for
+// ParseExprFrom. It is an error if src contains input other than whitespace
+// surrounding the object. The result is nil if src only contains a comment
+// and ParseComments is not set.
+//
+// If the source contains syntax errors, the result is a partial AST
+// (possibly nil) and the error is a scanner.ErrorList sorted by source position.
+func ParseObjFrom(fset *token.FileSet, filename, src string, mode Mode) (obj ast.Obj, err error) {
+	p := New(strings.NewReader(src), fset.AddFile(filename, -1, len(src)), WithMode(mode))
+	defer func() {
+		if e := recover(); e != nil {
+			// resume same panic if it's not a bailout
+			if _, ok := e.(bailout); !ok {
+				panic(e)
+			}
+		}
+
+		p.errors.Sort()
+		err = p.errors.Err()
+	}()
+
+	p.skipWhitespace()
+	obj = p.parseObj()
+	p.skipWhitespace()
+	p.expect(token.EOF)
+
+	return
+}
+
+// ParseObj is a convenience function for obtaining the AST of an object src.
+// Positions in the AST are relative to a new token.FileSet, so the first byte
+// of the source is at token.Pos(1). The filename used in error messages is the
+// empty string.
+func ParseObj(src string) (ast.Obj, error) {
+	return ParseObjFrom(token.NewFileSet(), "", src, 0)
 }
@@ -2,14 +2,17 @@ package parser_test
 
 import (
 	"bytes"
+	"errors"
 	gotoken "go/token"
 	"math"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/unmango/go-make/ast"
 	"github.com/unmango/go-make/parser"
+	"github.com/unmango/go-make/scanner"
 	"github.com/unmango/go-make/token"
 )
 
@@ -22,7 +25,7 @@ var _ = Describe("Parser", func() {
 
 	It("should Parse a comment", func() {
 		buf := bytes.NewBufferString("# comment text")
-		p := parser.New(buf, file)
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
 
 		f, err := p.ParseFile()
 
@@ -34,7 +37,7 @@ var _ = Describe("Parser", func() {
 
 	It("should Parse a comment group", func() {
 		buf := bytes.NewBufferString("# comment text\n# more text on this line")
-		p := parser.New(buf, file)
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
 
 		f, err := p.ParseFile()
 
@@ -49,7 +52,7 @@ var _ = Describe("Parser", func() {
 
 	It("should Parse multiple comment groups", func() {
 		buf := bytes.NewBufferString("# comment text\n\n# new comment group")
-		p := parser.New(buf, file)
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
 
 		f, err := p.ParseFile()
 
@@ -72,6 +75,7 @@ var _ = Describe("Parser", func() {
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
 			Colon: token.Pos(7),
 			Targets: []ast.Expr{&ast.Text{
 				Value:    "target",
@@ -79,7 +83,7 @@ var _ = Describe("Parser", func() {
 			}},
 			PreReqs:      []ast.Expr{},
 			OrderPreReqs: []ast.Expr{},
-			Recipes:      []*ast.Recipe{},
+			Recipes:      []ast.Obj{},
 		}))
 	})
 
@@ -94,16 +98,18 @@ var _ = Describe("Parser", func() {
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(f.Contents).To(ConsistOf(&ast.Rule{
+				Tok:   token.COLON,
 				Colon: token.Pos(7),
 				Targets: []ast.Expr{&ast.VarRef{
-					Dollar: token.Pos(1),
-					Open:   open,
-					Name:   name,
-					Close:  close,
+					Dollar:   token.Pos(1),
+					Open:     open,
+					Name:     []ast.Expr{&ast.Text{Value: name, ValuePos: token.Pos(3)}},
+					Close:    close,
+					ClosePos: token.Pos(6),
 				}},
 				PreReqs:      []ast.Expr{},
 				OrderPreReqs: []ast.Expr{},
-				Recipes:      []*ast.Recipe{},
+				Recipes:      []ast.Obj{},
 			}))
 		},
 	)
@@ -116,16 +122,17 @@ var _ = Describe("Parser", func() {
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
 			Colon: token.Pos(3),
 			Targets: []ast.Expr{&ast.VarRef{
 				Dollar: token.Pos(1),
 				Open:   token.ILLEGAL,
-				Name:   "f",
+				Name:   []ast.Expr{&ast.Text{Value: "f", ValuePos: token.Pos(2)}},
 				Close:  token.ILLEGAL,
 			}},
 			PreReqs:      []ast.Expr{},
 			OrderPreReqs: []ast.Expr{},
-			Recipes:      []*ast.Recipe{},
+			Recipes:      []ast.Obj{},
 		}))
 	})
 
@@ -137,352 +144,1620 @@ var _ = Describe("Parser", func() {
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
 			Colon: token.Pos(5),
 			Targets: []ast.Expr{
 				&ast.VarRef{
 					Dollar: token.Pos(1),
 					Open:   token.ILLEGAL,
-					Name:   "f",
+					Name:   []ast.Expr{&ast.Text{Value: "f", ValuePos: token.Pos(2)}},
 					Close:  token.ILLEGAL,
 				},
 				&ast.Text{Value: "oo", ValuePos: token.Pos(3)},
 			},
 			PreReqs:      []ast.Expr{},
 			OrderPreReqs: []ast.Expr{},
-			Recipes:      []*ast.Recipe{},
+			Recipes:      []ast.Obj{},
 		}))
 	})
 
-	DescribeTable("should error when variable reference has no closing token",
-		Entry(nil, "${foo:"),
-		Entry(nil, "$(foo:"),
-		func(input string) {
+	DescribeTable("should Parse a computed variable reference",
+		Entry(nil, "X := $($(ARCH)_CFLAGS)", token.LPAREN, token.RPAREN),
+		Entry(nil, "X := ${${ARCH}_CFLAGS}", token.LBRACE, token.RBRACE),
+		func(input string, open, close token.Token) {
 			buf := bytes.NewBufferString(input)
 			p := parser.New(buf, file)
 
-			_, err := p.ParseFile()
+			f, err := p.ParseFile()
 
-			Expect(err).To(MatchError("test:1:6: expected one of ')', '}', found ':'"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Contents).To(ConsistOf(&ast.Variable{
+				Name:  &ast.Text{Value: "X", ValuePos: token.Pos(1)},
+				Op:    token.SIMPLE_ASSIGN,
+				OpPos: token.Pos(3),
+				Value: []ast.Expr{&ast.VarRef{
+					Dollar: token.Pos(6),
+					Open:   open,
+					Name: []ast.Expr{
+						&ast.VarRef{
+							Dollar:   token.Pos(8),
+							Open:     open,
+							Name:     []ast.Expr{&ast.Text{Value: "ARCH", ValuePos: token.Pos(10)}},
+							Close:    close,
+							ClosePos: token.Pos(14),
+						},
+						&ast.Text{Value: "_CFLAGS", ValuePos: token.Pos(15)},
+					},
+					Close:    close,
+					ClosePos: token.Pos(22),
+				}},
+			}))
 		},
 	)
 
-	It("should Parse a rule with multiple targets", func() {
-		buf := bytes.NewBufferString("target target2:")
+	It("should Parse a deeply nested variable reference", func() {
+		buf := bytes.NewBufferString("$(a$($(b)))c:")
 		p := parser.New(buf, file)
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Colon: token.Pos(15),
+			Tok:   token.COLON,
+			Colon: token.Pos(13),
 			Targets: []ast.Expr{
-				&ast.Text{Value: "target", ValuePos: token.Pos(1)},
-				&ast.Text{Value: "target2", ValuePos: token.Pos(8)},
+				&ast.VarRef{
+					Dollar: token.Pos(1),
+					Open:   token.LPAREN,
+					Name: []ast.Expr{
+						&ast.Text{Value: "a", ValuePos: token.Pos(3)},
+						&ast.VarRef{
+							Dollar: token.Pos(4),
+							Open:   token.LPAREN,
+							Name: []ast.Expr{&ast.VarRef{
+								Dollar:   token.Pos(6),
+								Open:     token.LPAREN,
+								Name:     []ast.Expr{&ast.Text{Value: "b", ValuePos: token.Pos(8)}},
+								Close:    token.RPAREN,
+								ClosePos: token.Pos(9),
+							}},
+							Close:    token.RPAREN,
+							ClosePos: token.Pos(10),
+						},
+					},
+					Close:    token.RPAREN,
+					ClosePos: token.Pos(11),
+				},
+				&ast.Text{Value: "c", ValuePos: token.Pos(12)},
 			},
 			PreReqs:      []ast.Expr{},
 			OrderPreReqs: []ast.Expr{},
-			Recipes:      []*ast.Recipe{},
+			Recipes:      []ast.Obj{},
 		}))
 	})
 
-	It("should Parse a target with a prereq", func() {
-		buf := bytes.NewBufferString("target: prereq")
+	It("should Parse a variable reference with whitespace before the closing token", func() {
+		buf := bytes.NewBufferString("$(CC ):")
 		p := parser.New(buf, file)
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
 			Colon: token.Pos(7),
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
-			}},
-			PreReqs: []ast.Expr{&ast.Text{
-				Value:    "prereq",
-				ValuePos: token.Pos(9),
+			Targets: []ast.Expr{&ast.VarRef{
+				Dollar:   token.Pos(1),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "CC", ValuePos: token.Pos(3)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(6),
 			}},
+			PreReqs:      []ast.Expr{},
 			OrderPreReqs: []ast.Expr{},
-			Recipes:      []*ast.Recipe{},
+			Recipes:      []ast.Obj{},
 		}))
 	})
 
-	It("should Parse a target with multiple prereqs", func() {
-		buf := bytes.NewBufferString("target: prereq prereq2")
+	It("should Parse an empty variable reference", func() {
+		buf := bytes.NewBufferString("$():")
 		p := parser.New(buf, file)
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Colon: token.Pos(7),
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
+			Tok:   token.COLON,
+			Colon: token.Pos(4),
+			Targets: []ast.Expr{&ast.VarRef{
+				Dollar:   token.Pos(1),
+				Open:     token.LPAREN,
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(3),
 			}},
-			PreReqs: []ast.Expr{
-				&ast.Text{Value: "prereq", ValuePos: token.Pos(9)},
-				&ast.Text{Value: "prereq2", ValuePos: token.Pos(16)},
-			},
+			PreReqs:      []ast.Expr{},
 			OrderPreReqs: []ast.Expr{},
-			Recipes:      []*ast.Recipe{},
+			Recipes:      []ast.Obj{},
 		}))
 	})
 
-	It("should Parse a target with a prereq variable reference", func() {
-		buf := bytes.NewBufferString("target: ${FOO}")
+	It("should error when a variable name contains whitespace", func() {
+		buf := bytes.NewBufferString("$(foo bar):")
+		p := parser.New(buf, file)
+
+		_, err := p.ParseFile()
+
+		Expect(err).To(MatchError("test:1:7: expected one of ')', '}', found bar"))
+	})
+
+	It("should Parse a variable with modifiers", func() {
+		buf := bytes.NewBufferString("override export GOBIN := ${LOCALBIN}")
 		p := parser.New(buf, file)
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Colon: token.Pos(7),
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
-			}},
-			PreReqs: []ast.Expr{&ast.VarRef{
-				Dollar: token.Pos(9),
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Modifiers: []*ast.Modifier{
+				{Tok: token.OVERRIDE, TokPos: token.Pos(1)},
+				{Tok: token.EXPORT, TokPos: token.Pos(10)},
+			},
+			Name: &ast.Text{
+				Value:    "GOBIN",
+				ValuePos: token.Pos(17),
+			},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(23),
+			Value: []ast.Expr{&ast.VarRef{
+				Dollar: token.Pos(26),
 				Open:   token.LBRACE,
-				Name:   "FOO",
-				Close:  token.RBRACE,
+				Name: []ast.Expr{&ast.Text{
+					Value:    "LOCALBIN",
+					ValuePos: token.Pos(28),
+				}},
+				Close:    token.RBRACE,
+				ClosePos: token.Pos(36),
 			}},
-			OrderPreReqs: []ast.Expr{},
-			Recipes:      []*ast.Recipe{},
 		}))
 	})
 
-	It("should Parse a target with an order-only prereq", func() {
-		buf := bytes.NewBufferString("target: | prereq")
+	It("should Parse an export directive", func() {
+		buf := bytes.NewBufferString("export FOO $(BAR)")
 		p := parser.New(buf, file)
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
-			}},
-			Colon:   token.Pos(7),
-			Pipe:    token.Pos(9),
-			PreReqs: []ast.Expr{},
-			OrderPreReqs: []ast.Expr{
-				&ast.Text{Value: "prereq", ValuePos: token.Pos(11)},
+		Expect(f.Contents).To(ConsistOf(&ast.ExportDir{
+			Export: token.Pos(1),
+			Names: []ast.Expr{
+				&ast.Text{Value: "FOO", ValuePos: token.Pos(8)},
+				&ast.VarRef{
+					Dollar:   token.Pos(12),
+					Open:     token.LPAREN,
+					Name:     []ast.Expr{&ast.Text{Value: "BAR", ValuePos: token.Pos(14)}},
+					Close:    token.RPAREN,
+					ClosePos: token.Pos(17),
+				},
 			},
-			Recipes: []*ast.Recipe{},
 		}))
 	})
 
-	It("should Parse a target with a recipe", func() {
-		buf := bytes.NewBufferString("target:\n\trecipe")
+	It("should Parse a bare unexport directive", func() {
+		buf := bytes.NewBufferString("unexport\n")
 		p := parser.New(buf, file)
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Colon: token.Pos(7),
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
-			}},
-			PreReqs:      []ast.Expr{},
-			OrderPreReqs: []ast.Expr{},
-			Recipes: []*ast.Recipe{{
-				Prefix:    token.TAB,
-				PrefixPos: token.Pos(9),
-				Text: ast.Text{
-					Value:    "recipe",
-					ValuePos: token.Pos(10),
-				},
-			}},
-		}))
+		Expect(f.Contents).To(ConsistOf(&ast.UnexportDir{Unexport: token.Pos(1)}))
 	})
 
-	It("should Parse a target with multiple recipes", func() {
-		buf := bytes.NewBufferString("target:\n\trecipe\n\trecipe2")
-		p := parser.New(buf, file)
+	It("should Parse an export directive with a trailing comment", func() {
+		buf := bytes.NewBufferString("export FOO # comment\n")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Colon: token.Pos(7),
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
-			}},
-			PreReqs:      []ast.Expr{},
-			OrderPreReqs: []ast.Expr{},
-			Recipes: []*ast.Recipe{
-				{
-					Prefix:    token.TAB,
-					PrefixPos: token.Pos(9),
-					Text: ast.Text{
-						Value:    "recipe",
-						ValuePos: token.Pos(10),
-					},
-				},
-				{
-					Prefix:    token.TAB,
-					PrefixPos: token.Pos(17),
-					Text: ast.Text{
-						Value:    "recipe2",
-						ValuePos: token.Pos(18),
-					},
-				},
-			},
+		Expect(f.Contents).To(ConsistOf(&ast.ExportDir{
+			Export:  token.Pos(1),
+			Names:   []ast.Expr{&ast.Text{Value: "FOO", ValuePos: token.Pos(8)}},
+			Comment: &ast.Comment{Pound: token.Pos(12), Text: "comment"},
 		}))
 	})
 
-	It("should Parse a target with spaces in the recipe", func() {
-		buf := bytes.NewBufferString("target:\n\trecipe part2")
-		p := parser.New(buf, file)
+	It("should Parse a bare unexport directive with a trailing comment", func() {
+		buf := bytes.NewBufferString("unexport # comment\n")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Colon: token.Pos(7),
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
-			}},
-			PreReqs:      []ast.Expr{},
-			OrderPreReqs: []ast.Expr{},
-			Recipes: []*ast.Recipe{{
-				Prefix:    token.TAB,
-				PrefixPos: token.Pos(9),
-				Text: ast.Text{
-					Value:    "recipe part2",
-					ValuePos: token.Pos(10),
-				},
-			}},
+		Expect(f.Contents).To(ConsistOf(&ast.UnexportDir{
+			Unexport: token.Pos(1),
+			Comment:  &ast.Comment{Pound: token.Pos(10), Text: "comment"},
 		}))
 	})
 
-	It("should preserve double quotes in a recipe", func() {
-		buf := bytes.NewBufferString("target:\n\t@echo \"testing has been started...\"")
-		p := parser.New(buf, file)
+	It("should Parse an undefine directive with a trailing comment", func() {
+		buf := bytes.NewBufferString("undefine FOO # comment\n")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Colon: token.Pos(7),
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
-			}},
-			PreReqs:      []ast.Expr{},
-			OrderPreReqs: []ast.Expr{},
-			Recipes: []*ast.Recipe{{
-				Prefix:    token.TAB,
-				PrefixPos: token.Pos(9),
-				Text: ast.Text{
-					Value:    "@echo \"testing has been started...\"",
-					ValuePos: token.Pos(10),
-				},
-			}},
+		Expect(f.Contents).To(ConsistOf(&ast.UndefineDir{
+			Undefine: token.Pos(1),
+			Name:     &ast.Text{Value: "FOO", ValuePos: token.Pos(10)},
+			Comment:  &ast.Comment{Pound: token.Pos(14), Text: "comment"},
 		}))
 	})
 
-	It("should preserve comments and operator tokens in a recipe", func() {
-		buf := bytes.NewBufferString("target:\n\t@echo $@: # keep this comment")
+	It("should Parse an undefine directive", func() {
+		buf := bytes.NewBufferString("override undefine FOO")
 		p := parser.New(buf, file)
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Colon: token.Pos(7),
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
-			}},
-			PreReqs:      []ast.Expr{},
-			OrderPreReqs: []ast.Expr{},
-			Recipes: []*ast.Recipe{{
-				Prefix:    token.TAB,
-				PrefixPos: token.Pos(9),
-				Text: ast.Text{
-					Value:    "@echo $@: # keep this comment",
-					ValuePos: token.Pos(10),
-				},
-			}},
+		Expect(f.Contents).To(ConsistOf(&ast.UndefineDir{
+			Modifiers: []*ast.Modifier{{Tok: token.OVERRIDE, TokPos: token.Pos(1)}},
+			Undefine:  token.Pos(10),
+			Name:      &ast.Text{Value: "FOO", ValuePos: token.Pos(19)},
 		}))
 	})
 
-	It("should Parse a target with a prereq and a recipe", func() {
-		buf := bytes.NewBufferString("target: prereq\n\trecipe")
+	DescribeTable("should Parse a define directive with modifiers",
+		Entry(nil, "export define FOO\nbar\nendef", token.EXPORT),
+		Entry(nil, "override define FOO\nbar\nendef", token.OVERRIDE),
+		func(input string, mod token.Token) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			f, err := p.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			n := token.Pos(len(mod.String()) + 1)
+			Expect(f.Contents).To(ConsistOf(&ast.DefineDir{
+				Modifiers: []*ast.Modifier{{Tok: mod, TokPos: token.Pos(1)}},
+				Define:    n + 1,
+				Name:      &ast.Text{Value: "FOO", ValuePos: n + 8},
+				Op:        token.ILLEGAL,
+				Body:      []*ast.Text{{Value: "bar", ValuePos: n + 12}},
+				Endef:     n + 16,
+			}))
+		},
+	)
+
+	It("should error when export is followed by a rule", func() {
+		buf := bytes.NewBufferString("export foo: bar\n")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).To(MatchError("test:1:11: expected assignment operator, found ':'"))
+		Expect(f.Contents).To(ConsistOf(&ast.BadObj{From: token.Pos(1), To: token.Pos(16)}))
+	})
+
+	It("should error when override has no assignment", func() {
+		buf := bytes.NewBufferString("override FOO")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).To(MatchError("test:1:13: expected assignment operator, found 'EOF'"))
+		Expect(f.Contents).To(ConsistOf(&ast.BadObj{From: token.Pos(1), To: token.Pos(13)}))
+	})
+
+	It("should Parse a vpath directive", func() {
+		buf := bytes.NewBufferString("vpath %.c src:lib ../foo")
 		p := parser.New(buf, file)
 
 		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(f.Contents).To(ConsistOf(&ast.Rule{
-			Colon: token.Pos(7),
-			Targets: []ast.Expr{&ast.Text{
-				Value:    "target",
-				ValuePos: token.Pos(1),
-			}},
-			PreReqs: []ast.Expr{&ast.Text{
-				Value:    "prereq",
-				ValuePos: token.Pos(9),
-			}},
-			OrderPreReqs: []ast.Expr{},
-			Recipes: []*ast.Recipe{{
-				Prefix:    token.TAB,
-				PrefixPos: token.Pos(16),
-				Text: ast.Text{
-					Value:    "recipe",
-					ValuePos: token.Pos(17),
-				},
-			}},
+		Expect(f.Contents).To(ConsistOf(&ast.VpathDir{
+			Vpath:   token.Pos(1),
+			Pattern: &ast.Text{Value: "%.c", ValuePos: token.Pos(7)},
+			Directories: []ast.Expr{
+				&ast.Text{Value: "src", ValuePos: token.Pos(11)},
+				&ast.Text{Value: "lib", ValuePos: token.Pos(15)},
+				&ast.Text{Value: "../foo", ValuePos: token.Pos(19)},
+			},
+			Colons: []token.Pos{token.Pos(14)},
 		}))
 	})
 
-	It("should support a nil *token.File value", func() {
-		buf := bytes.NewBufferString("target:")
-		s := parser.New(buf, nil)
+	It("should Parse a vpath directive with a trailing comment", func() {
+		buf := bytes.NewBufferString("vpath %.c src # comment\n")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
 
-		f, err := s.ParseFile()
+		f, err := p.ParseFile()
 
 		Expect(err).NotTo(HaveOccurred())
-		Expect(f.Contents).NotTo(BeEmpty())
+		Expect(f.Contents).To(ConsistOf(&ast.VpathDir{
+			Vpath:       token.Pos(1),
+			Pattern:     &ast.Text{Value: "%.c", ValuePos: token.Pos(7)},
+			Directories: []ast.Expr{&ast.Text{Value: "src", ValuePos: token.Pos(11)}},
+			Comment:     &ast.Comment{Pound: token.Pos(15), Text: "comment"},
+		}))
 	})
 
-	DescribeTable("should parse a variable definition",
-		func(input string, op token.Token, vpos int) {
-			buf := bytes.NewBufferString(input)
-			s := parser.New(buf, file)
+	DescribeTable("should Parse a vpath directive clearing search paths",
+		Entry("for a pattern", "vpath %.c", &ast.VpathDir{
+			Vpath:   token.Pos(1),
+			Pattern: &ast.Text{Value: "%.c", ValuePos: token.Pos(7)},
+		}),
+		Entry("for all patterns", "vpath\n", &ast.VpathDir{
+			Vpath: token.Pos(1),
+		}),
+		func(input string, expected *ast.VpathDir) {
+			p := parser.New(bytes.NewBufferString(input), file)
 
-			f, err := s.ParseFile()
+			f, err := p.ParseFile()
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Expect(f.Contents).To(ConsistOf(expected))
+		},
+	)
+
+	It("should return a partial file when the source has errors", func() {
+		buf := bytes.NewBufferString("foo\nVAR = 1\n")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).To(MatchError("test:1:4: expected ':' or assignment, found '\n'"))
+		Expect(f.Contents).To(ConsistOf(
+			&ast.BadObj{From: token.Pos(1), To: token.Pos(4)},
+			&ast.Variable{
 				Name: &ast.Text{
 					Value:    "VAR",
-					ValuePos: token.Pos(1),
+					ValuePos: token.Pos(5),
 				},
-				Op:    op,
-				OpPos: token.Pos(5),
+				Op:    token.RECURSIVE_ASSIGN,
+				OpPos: token.Pos(9),
 				Value: []ast.Expr{&ast.Text{
-					Value:    "test",
-					ValuePos: token.Pos(vpos),
+					Value:    "1",
+					ValuePos: token.Pos(11),
 				}},
-			}))
-		},
-		Entry(nil, "VAR := test", token.SIMPLE_ASSIGN, 8),
-		Entry(nil, "VAR ::= test", token.POSIX_ASSIGN, 9),
-		Entry(nil, "VAR :::= test", token.IMMEDIATE_ASSIGN, 10),
-		Entry(nil, "VAR != test", token.SHELL_ASSIGN, 8),
+			},
+		))
+	})
+
+	It("should resynchronize at the next line after an error", func() {
+		buf := bytes.NewBufferString("$(foo bar) baz\ntarget:\n")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).To(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(2))
+		Expect(f.Contents[0]).To(Equal(&ast.BadObj{From: token.Pos(1), To: token.Pos(15)}))
+		_, ok := f.Contents[1].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should report one error per line", func() {
+		buf := bytes.NewBufferString("foo bar\n) baz\n")
+		p := parser.New(buf, file)
+
+		_, err := p.ParseFile()
+
+		var errs scanner.ErrorList
+		Expect(errors.As(err, &errs)).To(BeTrue())
+		Expect(errs).To(HaveLen(2))
+	})
+
+	It("should stop after the first 10 errors", func() {
+		buf := bytes.NewBufferString(strings.Repeat("foo\n", 12))
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		var errs scanner.ErrorList
+		Expect(errors.As(err, &errs)).To(BeTrue())
+		Expect(errs).To(HaveLen(10))
+		Expect(f).NotTo(BeNil())
+	})
+
+	It("should report all errors in AllErrors mode", func() {
+		buf := bytes.NewBufferString(strings.Repeat("foo\n", 12) + "$(foo bar) baz\n")
+		p := parser.New(buf, file, parser.WithMode(parser.AllErrors))
+
+		f, err := p.ParseFile()
+
+		var errs scanner.ErrorList
+		Expect(errors.As(err, &errs)).To(BeTrue())
+		Expect(errs).To(HaveLen(14))
+		Expect(f.Contents).To(HaveLen(13))
+	})
+
+	It("should print a trace in Trace mode", func() {
+		buf := bytes.NewBufferString("target: prereq")
+		out := &bytes.Buffer{}
+		p := parser.New(buf, file,
+			parser.WithMode(parser.Trace),
+			parser.WithTraceOutput(out),
+		)
+
+		_, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(HavePrefix(`    1:  1: "TEXT" "target"` + "\n" + `    1:  1: File (`))
+		Expect(out.String()).To(ContainSubstring(`    1:  7: . . Rule (`))
+		Expect(strings.HasSuffix(out.String(), `    1: 15: )`+"\n")).To(BeTrue())
+	})
+
+	DescribeTable("should error when variable reference has no closing token",
+		Entry(nil, "${foo"),
+		Entry(nil, "$(foo"),
+		func(input string) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			_, err := p.ParseFile()
+
+			Expect(err).To(MatchError("test:1:6: expected one of ')', '}', found 'EOF'"))
+		},
+	)
+
+	DescribeTable("should Parse a substitution reference",
+		Entry(nil, "X := $(SRCS:.c=.o)", token.LPAREN, ".c", 15, ".o", token.RPAREN),
+		Entry(nil, "X := ${SRCS:.c=.o}", token.LBRACE, ".c", 15, ".o", token.RBRACE),
+		Entry(nil, "X := $(SRCS:%.c=%.o)", token.LPAREN, "%.c", 16, "%.o", token.RPAREN),
+		func(input string, open token.Token, from string, assign int, to string, close token.Token) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			f, err := p.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Contents).To(ConsistOf(&ast.Variable{
+				Name:  &ast.Text{Value: "X", ValuePos: token.Pos(1)},
+				Op:    token.SIMPLE_ASSIGN,
+				OpPos: token.Pos(3),
+				Value: []ast.Expr{&ast.SubstRef{
+					Dollar:   token.Pos(6),
+					Open:     open,
+					Name:     []ast.Expr{&ast.Text{Value: "SRCS", ValuePos: token.Pos(8)}},
+					Colon:    token.Pos(12),
+					From:     []ast.Expr{&ast.Text{Value: from, ValuePos: token.Pos(13)}},
+					Assign:   token.Pos(assign),
+					To:       []ast.Expr{&ast.Text{Value: to, ValuePos: token.Pos(assign + 1)}},
+					Close:    close,
+					ClosePos: token.Pos(assign + len(to) + 1),
+				}},
+			}))
+		},
+	)
+
+	It("should Parse a substitution reference with an empty suffix", func() {
+		buf := bytes.NewBufferString("X := $(OBJS:=.o)")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Name:  &ast.Text{Value: "X", ValuePos: token.Pos(1)},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(3),
+			Value: []ast.Expr{&ast.SubstRef{
+				Dollar:   token.Pos(6),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "OBJS", ValuePos: token.Pos(8)}},
+				Colon:    token.Pos(12),
+				Assign:   token.Pos(13),
+				To:       []ast.Expr{&ast.Text{Value: ".o", ValuePos: token.Pos(14)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(16),
+			}},
+		}))
+	})
+
+	It("should Parse a substitution reference containing variable references", func() {
+		buf := bytes.NewBufferString("X := $(SRCS:%=$(DIR)/%)")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Name:  &ast.Text{Value: "X", ValuePos: token.Pos(1)},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(3),
+			Value: []ast.Expr{&ast.SubstRef{
+				Dollar: token.Pos(6),
+				Open:   token.LPAREN,
+				Name:   []ast.Expr{&ast.Text{Value: "SRCS", ValuePos: token.Pos(8)}},
+				Colon:  token.Pos(12),
+				From:   []ast.Expr{&ast.Text{Value: "%", ValuePos: token.Pos(13)}},
+				Assign: token.Pos(14),
+				To: []ast.Expr{
+					&ast.VarRef{
+						Dollar:   token.Pos(15),
+						Open:     token.LPAREN,
+						Name:     []ast.Expr{&ast.Text{Value: "DIR", ValuePos: token.Pos(17)}},
+						Close:    token.RPAREN,
+						ClosePos: token.Pos(20),
+					},
+					&ast.Text{Value: "/%", ValuePos: token.Pos(21)},
+				},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(23),
+			}},
+		}))
+	})
+
+	DescribeTable("should error when a substitution reference has no '='",
+		Entry(nil, "$(foo:", "test:1:7: expected '=', found 'EOF'"),
+		Entry(nil, "$(foo:bar)", "test:1:10: expected '=', found ')'"),
+		func(input, expected string) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			_, err := p.ParseFile()
+
+			Expect(err).To(MatchError(expected))
+		},
+	)
+
+	It("should Parse a conditional directive in recipes", func() {
+		buf := bytes.NewBufferString("build:\n\techo a\nifdef DEBUG\n\techo b\nendif\n\techo c")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(1))
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Recipes).To(HaveExactElements(
+			&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(8),
+				Text:      ast.Text{Value: "echo a", ValuePos: token.Pos(9)},
+			},
+			&ast.IfBlock{
+				Directive: &ast.IfdefDir{
+					Tok:     token.IFDEF,
+					TokPos:  token.Pos(16),
+					VarName: &ast.Text{Value: "DEBUG", ValuePos: token.Pos(22)},
+				},
+				Text: []ast.Obj{&ast.Recipe{
+					Prefix:    token.TAB,
+					PrefixPos: token.Pos(28),
+					Text:      ast.Text{Value: "echo b", ValuePos: token.Pos(29)},
+				}},
+				Endif: token.Pos(36),
+			},
+			&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(42),
+				Text:      ast.Text{Value: "echo c", ValuePos: token.Pos(43)},
+			},
+		))
+	})
+
+	It("should not Parse a conditional directive without recipes as recipes", func() {
+		buf := bytes.NewBufferString("build:\n\techo a\nifdef DEBUG\nFLAGS := -g\nendif\n")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(2))
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Recipes).To(HaveLen(1))
+		_, ok = f.Contents[1].(*ast.IfBlock)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should Parse recipes separated by an empty line", func() {
+		buf := bytes.NewBufferString("build:\n\techo a\n\n\techo b\n")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(1))
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Recipes).To(HaveLen(2))
+	})
+
+	It("should Parse a comment between recipes as part of the recipes", func() {
+		buf := bytes.NewBufferString("a:\n\techo 1\n# c\n\techo 2\n")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(1))
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Recipes).To(HaveExactElements(
+			&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(4),
+				Text:      ast.Text{Value: "echo 1", ValuePos: token.Pos(5)},
+			},
+			&ast.CommentGroup{List: []*ast.Comment{
+				{Pound: token.Pos(12), Text: "c"},
+			}},
+			&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(16),
+				Text:      ast.Text{Value: "echo 2", ValuePos: token.Pos(17)},
+			},
+		))
+	})
+
+	It("should not Parse a comment after the last recipe as part of the recipes", func() {
+		buf := bytes.NewBufferString("a:\n\techo 1\n# c\nb:\n")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(3))
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Recipes).To(HaveLen(1))
+		_, ok = f.Contents[1].(*ast.CommentGroup)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should not Parse a tab-prefixed line after a variable as a recipe", func() {
+		buf := bytes.NewBufferString("build:\nVAR := 1\n\tOTHER := 2\n")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(3))
+		_, ok := f.Contents[2].(*ast.Variable)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should Parse a grouped target rule", func() {
+		buf := bytes.NewBufferString("foo.h foo.c &: foo.y")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.AND_COLON,
+			Colon: token.Pos(13),
+			Targets: []ast.Expr{
+				&ast.Text{Value: "foo.h", ValuePos: token.Pos(1)},
+				&ast.Text{Value: "foo.c", ValuePos: token.Pos(7)},
+			},
+			PreReqs: []ast.Expr{&ast.Text{
+				Value:    "foo.y",
+				ValuePos: token.Pos(16),
+			}},
+			OrderPreReqs: []ast.Expr{},
+			Recipes:      []ast.Obj{},
+		}))
+	})
+
+	It("should Parse a double-colon rule", func() {
+		buf := bytes.NewBufferString("clean:: prereq\n\trm foo")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.DOUBLE_COLON,
+			Colon: token.Pos(6),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "clean",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs: []ast.Expr{&ast.Text{
+				Value:    "prereq",
+				ValuePos: token.Pos(9),
+			}},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(16),
+				Text: ast.Text{
+					Value:    "rm foo",
+					ValuePos: token.Pos(17),
+				},
+			}},
+		}))
+	})
+
+	It("should Parse a static pattern rule", func() {
+		buf := bytes.NewBufferString("$(OBJS): %.o: %.c | dir")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(8),
+			Targets: []ast.Expr{&ast.VarRef{
+				Dollar:   token.Pos(1),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "OBJS", ValuePos: token.Pos(3)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(7),
+			}},
+			Pattern:      []ast.Expr{&ast.Text{Value: "%.o", ValuePos: token.Pos(10)}},
+			PatternColon: token.Pos(13),
+			PreReqs:      []ast.Expr{&ast.Text{Value: "%.c", ValuePos: token.Pos(15)}},
+			Pipe:         token.Pos(19),
+			OrderPreReqs: []ast.Expr{&ast.Text{Value: "dir", ValuePos: token.Pos(21)}},
+			Recipes:      []ast.Obj{},
+		}))
+	})
+
+	It("should error when a static pattern rule has more than one target-pattern", func() {
+		buf := bytes.NewBufferString("a: b: c: d")
+		p := parser.New(buf, file)
+
+		_, err := p.ParseFile()
+
+		Expect(err).To(MatchError("test:1:8: expected one of 'TEXT', '$', found ':'"))
+	})
+
+	It("should Parse a rule with multiple targets", func() {
+		buf := bytes.NewBufferString("target target2:")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(15),
+			Targets: []ast.Expr{
+				&ast.Text{Value: "target", ValuePos: token.Pos(1)},
+				&ast.Text{Value: "target2", ValuePos: token.Pos(8)},
+			},
+			PreReqs:      []ast.Expr{},
+			OrderPreReqs: []ast.Expr{},
+			Recipes:      []ast.Obj{},
+		}))
+	})
+
+	It("should Parse a target with a prereq", func() {
+		buf := bytes.NewBufferString("target: prereq")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs: []ast.Expr{&ast.Text{
+				Value:    "prereq",
+				ValuePos: token.Pos(9),
+			}},
+			OrderPreReqs: []ast.Expr{},
+			Recipes:      []ast.Obj{},
+		}))
+	})
+
+	It("should Parse a target with multiple prereqs", func() {
+		buf := bytes.NewBufferString("target: prereq prereq2")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs: []ast.Expr{
+				&ast.Text{Value: "prereq", ValuePos: token.Pos(9)},
+				&ast.Text{Value: "prereq2", ValuePos: token.Pos(16)},
+			},
+			OrderPreReqs: []ast.Expr{},
+			Recipes:      []ast.Obj{},
+		}))
+	})
+
+	It("should Parse a target with a prereq variable reference", func() {
+		buf := bytes.NewBufferString("target: ${FOO}")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs: []ast.Expr{&ast.VarRef{
+				Dollar:   token.Pos(9),
+				Open:     token.LBRACE,
+				Name:     []ast.Expr{&ast.Text{Value: "FOO", ValuePos: token.Pos(11)}},
+				Close:    token.RBRACE,
+				ClosePos: token.Pos(14),
+			}},
+			OrderPreReqs: []ast.Expr{},
+			Recipes:      []ast.Obj{},
+		}))
+	})
+
+	It("should Parse a target with an order-only prereq", func() {
+		buf := bytes.NewBufferString("target: | prereq")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			Tok:     token.COLON,
+			Colon:   token.Pos(7),
+			Pipe:    token.Pos(9),
+			PreReqs: []ast.Expr{},
+			OrderPreReqs: []ast.Expr{
+				&ast.Text{Value: "prereq", ValuePos: token.Pos(11)},
+			},
+			Recipes: []ast.Obj{},
+		}))
+	})
+
+	It("should Parse a target with a recipe", func() {
+		buf := bytes.NewBufferString("target:\n\trecipe")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs:      []ast.Expr{},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(9),
+				Text: ast.Text{
+					Value:    "recipe",
+					ValuePos: token.Pos(10),
+				},
+			}},
+		}))
+	})
+
+	It("should Parse a target with multiple recipes", func() {
+		buf := bytes.NewBufferString("target:\n\trecipe\n\trecipe2")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs:      []ast.Expr{},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{
+				&ast.Recipe{
+					Prefix:    token.TAB,
+					PrefixPos: token.Pos(9),
+					Text: ast.Text{
+						Value:    "recipe",
+						ValuePos: token.Pos(10),
+					},
+				},
+				&ast.Recipe{
+					Prefix:    token.TAB,
+					PrefixPos: token.Pos(17),
+					Text: ast.Text{
+						Value:    "recipe2",
+						ValuePos: token.Pos(18),
+					},
+				},
+			},
+		}))
+	})
+
+	It("should Parse a target with spaces in the recipe", func() {
+		buf := bytes.NewBufferString("target:\n\trecipe part2")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs:      []ast.Expr{},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(9),
+				Text: ast.Text{
+					Value:    "recipe part2",
+					ValuePos: token.Pos(10),
+				},
+			}},
+		}))
+	})
+
+	It("should preserve double quotes in a recipe", func() {
+		buf := bytes.NewBufferString("target:\n\t@echo \"testing has been started...\"")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs:      []ast.Expr{},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:       token.TAB,
+				PrefixPos:    token.Pos(9),
+				Modifiers:    "@",
+				ModifiersPos: token.Pos(10),
+				Text: ast.Text{
+					Value:    "echo \"testing has been started...\"",
+					ValuePos: token.Pos(11),
+				},
+			}},
+		}))
+	})
+
+	It("should preserve comments and operator tokens in a recipe", func() {
+		buf := bytes.NewBufferString("target:\n\t@echo $@: # keep this comment")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs:      []ast.Expr{},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:       token.TAB,
+				PrefixPos:    token.Pos(9),
+				Modifiers:    "@",
+				ModifiersPos: token.Pos(10),
+				Text: ast.Text{
+					Value:    "echo $@: # keep this comment",
+					ValuePos: token.Pos(11),
+				},
+			}},
+		}))
+	})
+
+	DescribeTable("should Parse recipe modifiers",
+		func(input, modifiers string, valuePos int, silent, ignoreErrors, always bool) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			f, err := p.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Contents).To(HaveLen(1))
+			r, ok := f.Contents[0].(*ast.Rule)
+			Expect(ok).To(BeTrue())
+			Expect(r.Recipes).To(ConsistOf(&ast.Recipe{
+				Prefix:       token.TAB,
+				PrefixPos:    token.Pos(9),
+				Modifiers:    modifiers,
+				ModifiersPos: token.Pos(valuePos - len(modifiers)),
+				Text: ast.Text{
+					Value:    "rm foo",
+					ValuePos: token.Pos(valuePos),
+				},
+			}))
+			recipe, ok := r.Recipes[0].(*ast.Recipe)
+			Expect(ok).To(BeTrue())
+			Expect(recipe.Silent()).To(Equal(silent))
+			Expect(recipe.IgnoreErrors()).To(Equal(ignoreErrors))
+			Expect(recipe.Always()).To(Equal(always))
+		},
+		Entry(nil, "target:\n\t@rm foo", "@", 11, true, false, false),
+		Entry(nil, "target:\n\t-rm foo", "-", 11, false, true, false),
+		Entry(nil, "target:\n\t+rm foo", "+", 11, false, false, true),
+		Entry(nil, "target:\n\t@-+rm foo", "@-+", 13, true, true, true),
+		Entry(nil, "target:\n\t@ -rm foo", "@ -", 13, true, true, false),
+		Entry(nil, "target:\n\t  @rm foo", "@", 13, true, false, false),
+	)
+
+	It("should Parse inline recipe modifiers", func() {
+		buf := bytes.NewBufferString("all: ; @echo\nnext: ; echo")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(2))
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Recipes).To(ConsistOf(&ast.Recipe{
+			Prefix:       token.SEMI,
+			PrefixPos:    token.Pos(6),
+			Modifiers:    "@",
+			ModifiersPos: token.Pos(8),
+			Text: ast.Text{
+				Value:    "echo",
+				ValuePos: token.Pos(9),
+			},
+		}))
+		r, ok = f.Contents[1].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Recipes).To(ConsistOf(&ast.Recipe{
+			Prefix:    token.SEMI,
+			PrefixPos: token.Pos(20),
+			Text: ast.Text{
+				Value:    " echo",
+				ValuePos: token.Pos(21),
+			},
+		}))
+	})
+
+	It("should not Parse modifiers after the command", func() {
+		buf := bytes.NewBufferString("target:\n\trm -f foo")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		recipe, ok := r.Recipes[0].(*ast.Recipe)
+		Expect(ok).To(BeTrue())
+		Expect(recipe.Modifiers).To(BeEmpty())
+		Expect(recipe.Value).To(Equal("rm -f foo"))
+		Expect(recipe.IgnoreErrors()).To(BeFalse())
+	})
+
+	It("should Parse recipe expressions in ParseRecipes mode", func() {
+		buf := bytes.NewBufferString("target:\n\t@$(CC) -o $@")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseRecipes))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs:      []ast.Expr{},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:       token.TAB,
+				PrefixPos:    token.Pos(9),
+				Modifiers:    "@",
+				ModifiersPos: token.Pos(10),
+				Text: ast.Text{
+					Value:    "$(CC) -o $@",
+					ValuePos: token.Pos(11),
+				},
+				Exprs: []ast.Expr{
+					&ast.VarRef{
+						Dollar:   token.Pos(11),
+						Open:     token.LPAREN,
+						Name:     []ast.Expr{&ast.Text{Value: "CC", ValuePos: token.Pos(13)}},
+						Close:    token.RPAREN,
+						ClosePos: token.Pos(15),
+					},
+					&ast.Text{Value: " -o ", ValuePos: token.Pos(16)},
+					&ast.VarRef{
+						Dollar: token.Pos(20),
+						Open:   token.ILLEGAL,
+						Name:   []ast.Expr{&ast.Text{Value: "@", ValuePos: token.Pos(21)}},
+						Close:  token.ILLEGAL,
+					},
+				},
+			}},
+		}))
+	})
+
+	It("should associate doc comments in ParseComments mode", func() {
+		buf := bytes.NewBufferString("# Build the thing\nbuild:\n\n# unrelated\n\nVAR := 1\n# The other one\nOTHER = 2\n")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(6))
+		r, ok := f.Contents[1].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Doc).To(BeIdenticalTo(f.Contents[0]))
+		v, ok := f.Contents[3].(*ast.Variable)
+		Expect(ok).To(BeTrue())
+		Expect(v.Doc).To(BeNil())
+		v, ok = f.Contents[5].(*ast.Variable)
+		Expect(ok).To(BeTrue())
+		Expect(v.Doc).To(BeIdenticalTo(f.Contents[4]))
+	})
+
+	It("should drop comments by default", func() {
+		buf := bytes.NewBufferString("# Build the thing\nbuild: # trailing\n\t# recipe comment\n\techo\n")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(1))
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Doc).To(BeNil())
+		Expect(r.Comment).To(BeNil())
+		Expect(r.Recipes).To(HaveLen(2))
+	})
+
+	It("should Parse escaped dollar signs in recipe expressions", func() {
+		buf := bytes.NewBufferString("target:\n\techo $$HOME")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseRecipes))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		recipe, ok := r.Recipes[0].(*ast.Recipe)
+		Expect(ok).To(BeTrue())
+		Expect(recipe.Exprs).To(Equal([]ast.Expr{
+			&ast.Text{Value: "echo ", ValuePos: token.Pos(10)},
+			&ast.Escape{Dollar: token.Pos(15)},
+			&ast.Text{Value: "HOME", ValuePos: token.Pos(17)},
+		}))
+	})
+
+	It("should Parse references in recipe comments in ParseRecipes mode", func() {
+		buf := bytes.NewBufferString("target:\n\techo $(A) # $(B)")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseRecipes))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		recipe, ok := r.Recipes[0].(*ast.Recipe)
+		Expect(ok).To(BeTrue())
+		Expect(recipe.Value).To(Equal("echo $(A) # $(B)"))
+		Expect(recipe.Exprs).To(Equal([]ast.Expr{
+			&ast.Text{Value: "echo ", ValuePos: token.Pos(10)},
+			&ast.VarRef{
+				Dollar:   token.Pos(15),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "A", ValuePos: token.Pos(17)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(18),
+			},
+			&ast.Text{Value: " # ", ValuePos: token.Pos(19)},
+			&ast.VarRef{
+				Dollar:   token.Pos(22),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "B", ValuePos: token.Pos(24)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(25),
+			},
+		}))
+	})
+
+	It("should keep recipe comments with unbalanced references as text", func() {
+		buf := bytes.NewBufferString("target:\n\techo # $(B")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseRecipes))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		r, ok := f.Contents[0].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		recipe, ok := r.Recipes[0].(*ast.Recipe)
+		Expect(ok).To(BeTrue())
+		Expect(recipe.Exprs).To(Equal([]ast.Expr{
+			&ast.Text{Value: "echo # $(B", ValuePos: token.Pos(10)},
+		}))
+	})
+
+	It("should Parse an escaped dollar sign", func() {
+		buf := bytes.NewBufferString("VAR := $$HOME")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Name: &ast.Text{
+				Value:    "VAR",
+				ValuePos: token.Pos(1),
+			},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(5),
+			Value: []ast.Expr{
+				&ast.Escape{Dollar: token.Pos(8)},
+				&ast.Text{Value: "HOME", ValuePos: token.Pos(10)},
+			},
+		}))
+	})
+
+	It("should Parse a rule with a trailing comment", func() {
+		buf := bytes.NewBufferString("target: prereq # comment\n\trecipe")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs: []ast.Expr{&ast.Text{
+				Value:    "prereq",
+				ValuePos: token.Pos(9),
+			}},
+			OrderPreReqs: []ast.Expr{},
+			Comment: &ast.Comment{
+				Pound: token.Pos(16),
+				Text:  "comment",
+			},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(26),
+				Text: ast.Text{
+					Value:    "recipe",
+					ValuePos: token.Pos(27),
+				},
+			}},
+		}))
+	})
+
+	It("should Parse a variable with a trailing comment", func() {
+		buf := bytes.NewBufferString("VAR := x # note")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Name: &ast.Text{
+				Value:    "VAR",
+				ValuePos: token.Pos(1),
+			},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(5),
+			Value: []ast.Expr{&ast.Text{
+				Value:    "x",
+				ValuePos: token.Pos(8),
+			}},
+			Comment: &ast.Comment{
+				Pound: token.Pos(10),
+				Text:  "note",
+			},
+		}))
+	})
+
+	It("should Parse conditional directives with trailing comments", func() {
+		buf := bytes.NewBufferString("ifeq (a,b) # eq\nelse # ne\nendif # end")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(1))
+		b, ok := f.Contents[0].(*ast.IfBlock)
+		Expect(ok).To(BeTrue())
+		d, ok := b.Directive.(*ast.IfeqDir)
+		Expect(ok).To(BeTrue())
+		Expect(d.Comment).To(Equal(&ast.Comment{Pound: token.Pos(12), Text: "eq"}))
+		Expect(b.Else).To(HaveLen(1))
+		Expect(b.Else[0].Comment).To(Equal(&ast.Comment{Pound: token.Pos(22), Text: "ne"}))
+		Expect(b.EndifComment).To(Equal(&ast.Comment{Pound: token.Pos(33), Text: "end"}))
+	})
+
+	It("should Parse an ifdef directive with a trailing comment", func() {
+		buf := bytes.NewBufferString("ifdef FOO # def\nendif")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		b, ok := f.Contents[0].(*ast.IfBlock)
+		Expect(ok).To(BeTrue())
+		Expect(b.Directive).To(Equal(&ast.IfdefDir{
+			Tok:     token.IFDEF,
+			TokPos:  token.Pos(1),
+			VarName: &ast.Text{Value: "FOO", ValuePos: token.Pos(7)},
+			Comment: &ast.Comment{Pound: token.Pos(11), Text: "def"},
+		}))
+	})
+
+	It("should Parse a target with a prereq and a recipe", func() {
+		buf := bytes.NewBufferString("target: prereq\n\trecipe")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs: []ast.Expr{&ast.Text{
+				Value:    "prereq",
+				ValuePos: token.Pos(9),
+			}},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(16),
+				Text: ast.Text{
+					Value:    "recipe",
+					ValuePos: token.Pos(17),
+				},
+			}},
+		}))
+	})
+
+	It("should Parse an inline recipe", func() {
+		buf := bytes.NewBufferString("target: prereq ; recipe\n\tnext")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs: []ast.Expr{&ast.Text{
+				Value:    "prereq",
+				ValuePos: token.Pos(9),
+			}},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{
+				&ast.Recipe{
+					Prefix:    token.SEMI,
+					PrefixPos: token.Pos(16),
+					Text: ast.Text{
+						Value:    " recipe",
+						ValuePos: token.Pos(17),
+					},
+				},
+				&ast.Recipe{
+					Prefix:    token.TAB,
+					PrefixPos: token.Pos(25),
+					Text: ast.Text{
+						Value:    "next",
+						ValuePos: token.Pos(26),
+					},
+				},
+			},
+		}))
+	})
+
+	It("should Parse an inline recipe after order-only prereqs", func() {
+		buf := bytes.NewBufferString("target: | prereq;recipe")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs: []ast.Expr{},
+			Pipe:    token.Pos(9),
+			OrderPreReqs: []ast.Expr{&ast.Text{
+				Value:    "prereq",
+				ValuePos: token.Pos(11),
+			}},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:    token.SEMI,
+				PrefixPos: token.Pos(17),
+				Text: ast.Text{
+					Value:    "recipe",
+					ValuePos: token.Pos(18),
+				},
+			}},
+		}))
+	})
+
+	DescribeTable("should Parse a recipe with a custom .RECIPEPREFIX",
+		func(input string, prefix string, value string, valuePos int) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			f, err := p.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Contents).To(HaveLen(2))
+			Expect(f.Contents[1]).To(Equal(&ast.Rule{
+				Tok:   token.COLON,
+				Colon: token.Pos(26),
+				Targets: []ast.Expr{&ast.Text{
+					Value:    "target",
+					ValuePos: token.Pos(20),
+				}},
+				PreReqs:      []ast.Expr{},
+				OrderPreReqs: []ast.Expr{},
+				Recipes: []ast.Obj{&ast.Recipe{
+					Prefix:    token.TEXT,
+					PrefixPos: token.Pos(28),
+					PrefixLit: prefix,
+					Text: ast.Text{
+						Value:    value,
+						ValuePos: token.Pos(valuePos),
+					},
+				}},
+			}))
+		},
+		Entry(nil, ".RECIPEPREFIX := >\ntarget:\n>recipe", ">", "recipe", 29),
+		Entry(nil, ".RECIPEPREFIX := |\ntarget:\n|recipe", "|", "recipe", 29),
+		Entry(nil, ".RECIPEPREFIX := |\ntarget:\n| recipe", "|", " recipe", 29),
+	)
+
+	It("should Parse a tab indented line after a rule with a custom .RECIPEPREFIX", func() {
+		buf := bytes.NewBufferString(".RECIPEPREFIX := >\nall:\n>echo\n\tA := 1\n")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(3))
+		Expect(f.Contents[2]).To(Equal(&ast.Variable{
+			Name:  &ast.Text{Value: "A", ValuePos: token.Pos(32)},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(34),
+			Value: []ast.Expr{&ast.Text{Value: "1", ValuePos: token.Pos(37)}},
+		}))
+		Expect(f.Tabs).To(Equal([]token.Pos{31}))
+	})
+
+	It("should reset the recipe prefix when .RECIPEPREFIX is empty", func() {
+		buf := bytes.NewBufferString(".RECIPEPREFIX := >\n.RECIPEPREFIX :=\ntarget:\n\trecipe")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveLen(3))
+		r, ok := f.Contents[2].(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Recipes).To(ConsistOf(&ast.Recipe{
+			Prefix:    token.TAB,
+			PrefixPos: token.Pos(45),
+			Text: ast.Text{
+				Value:    "recipe",
+				ValuePos: token.Pos(46),
+			},
+		}))
+	})
+
+	It("should support a nil *token.File value", func() {
+		buf := bytes.NewBufferString("target:")
+		s := parser.New(buf, nil)
+
+		f, err := s.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).NotTo(BeEmpty())
+	})
+
+	DescribeTable("should parse a variable definition",
+		func(input string, op token.Token, vpos int) {
+			buf := bytes.NewBufferString(input)
+			s := parser.New(buf, file)
+
+			f, err := s.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Contents).To(ConsistOf(&ast.Variable{
+				Name: &ast.Text{
+					Value:    "VAR",
+					ValuePos: token.Pos(1),
+				},
+				Op:    op,
+				OpPos: token.Pos(5),
+				Value: []ast.Expr{&ast.Text{
+					Value:    "test",
+					ValuePos: token.Pos(vpos),
+				}},
+			}))
+		},
+		Entry(nil, "VAR := test", token.SIMPLE_ASSIGN, 8),
+		Entry(nil, "VAR ::= test", token.POSIX_ASSIGN, 9),
+		Entry(nil, "VAR :::= test", token.IMMEDIATE_ASSIGN, 10),
+		Entry(nil, "VAR != test", token.SHELL_ASSIGN, 8),
+		Entry(nil, "VAR += test", token.APPEND_ASSIGN, 8),
 		Entry(nil, "VAR ?= test", token.IFNDEF_ASSIGN, 8),
 		Entry(nil, "VAR = test", token.RECURSIVE_ASSIGN, 7),
 	)
 
+	DescribeTable("should parse a variable definition without a space before the operator",
+		func(input string, op token.Token, vpos int) {
+			buf := bytes.NewBufferString(input)
+			s := parser.New(buf, file)
+
+			f, err := s.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Contents).To(ConsistOf(&ast.Variable{
+				Name: &ast.Text{
+					Value:    "VAR",
+					ValuePos: token.Pos(1),
+				},
+				Op:    op,
+				OpPos: token.Pos(4),
+				Value: []ast.Expr{&ast.Text{
+					Value:    "test",
+					ValuePos: token.Pos(vpos),
+				}},
+			}))
+		},
+		Entry(nil, "VAR:= test", token.SIMPLE_ASSIGN, 7),
+		Entry(nil, "VAR!= test", token.SHELL_ASSIGN, 7),
+		Entry(nil, "VAR+= test", token.APPEND_ASSIGN, 7),
+		Entry(nil, "VAR?= test", token.IFNDEF_ASSIGN, 7),
+		Entry(nil, "VAR+=test", token.APPEND_ASSIGN, 6),
+	)
+
 	DescribeTable("should parse a space-separated variable definition",
 		func(input string, op token.Token, vpos int) {
 			buf := bytes.NewBufferString(input)
@@ -514,6 +1789,7 @@ var _ = Describe("Parser", func() {
 		Entry(nil, "VAR ::= test test2", token.POSIX_ASSIGN, 9),
 		Entry(nil, "VAR :::= test test2", token.IMMEDIATE_ASSIGN, 10),
 		Entry(nil, "VAR != test test2", token.SHELL_ASSIGN, 8),
+		Entry(nil, "VAR += test test2", token.APPEND_ASSIGN, 8),
 		Entry(nil, "VAR ?= test test2", token.IFNDEF_ASSIGN, 8),
 		Entry(nil, "VAR = test test2", token.RECURSIVE_ASSIGN, 7),
 	)
@@ -523,27 +1799,645 @@ var _ = Describe("Parser", func() {
 			buf := bytes.NewBufferString(input)
 			s := parser.New(buf, file)
 
-			f, err := s.ParseFile()
+			f, err := s.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Contents).To(ConsistOf(&ast.Variable{
+				Name: &ast.Text{
+					Value:    "VAR",
+					ValuePos: token.Pos(1),
+				},
+				Op:    op,
+				OpPos: token.Pos(5),
+				Value: nil,
+			}))
+		},
+		Entry(nil, "VAR :=", token.SIMPLE_ASSIGN),
+		Entry(nil, "VAR ::=", token.POSIX_ASSIGN),
+		Entry(nil, "VAR :::=", token.IMMEDIATE_ASSIGN),
+		Entry(nil, "VAR !=", token.SHELL_ASSIGN),
+		Entry(nil, "VAR +=", token.APPEND_ASSIGN),
+		Entry(nil, "VAR ?=", token.IFNDEF_ASSIGN),
+		Entry(nil, "VAR =", token.RECURSIVE_ASSIGN),
+	)
+
+	It("should Parse a continued variable definition", func() {
+		buf := bytes.NewBufferString("VAR := a \\\n\tb")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Name: &ast.Text{
+				Value:    "VAR",
+				ValuePos: token.Pos(1),
+			},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(5),
+			Value: []ast.Expr{
+				&ast.Text{
+					Value:    "a",
+					ValuePos: token.Pos(8),
+				},
+				&ast.Continuation{
+					Backslash: token.Pos(10),
+					Text:      "\\\n\t",
+				},
+				&ast.Text{
+					Value:    "b",
+					ValuePos: token.Pos(13),
+				},
+			},
+		}))
+	})
+
+	It("should Parse continued prereqs", func() {
+		buf := bytes.NewBufferString("target: a \\\n  b")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs: []ast.Expr{
+				&ast.Text{
+					Value:    "a",
+					ValuePos: token.Pos(9),
+				},
+				&ast.Continuation{
+					Backslash: token.Pos(11),
+					Text:      "\\\n  ",
+				},
+				&ast.Text{
+					Value:    "b",
+					ValuePos: token.Pos(15),
+				},
+			},
+			OrderPreReqs: []ast.Expr{},
+			Recipes:      []ast.Obj{},
+		}))
+	})
+
+	It("should preserve continued lines in a recipe", func() {
+		buf := bytes.NewBufferString("target:\n\techo a \\\n\t  b")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.Text{
+				Value:    "target",
+				ValuePos: token.Pos(1),
+			}},
+			PreReqs:      []ast.Expr{},
+			OrderPreReqs: []ast.Expr{},
+			Recipes: []ast.Obj{&ast.Recipe{
+				Prefix:    token.TAB,
+				PrefixPos: token.Pos(9),
+				Text: ast.Text{
+					Value:    "echo a \\\n\t  b",
+					ValuePos: token.Pos(10),
+				},
+			}},
+		}))
+	})
+
+	It("should Parse a target-specific variable", func() {
+		buf := bytes.NewBufferString("debug: CFLAGS += -g")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.TargetVariable{
+			Targets: []ast.Expr{&ast.Text{Value: "debug", ValuePos: token.Pos(1)}},
+			Tok:     token.COLON,
+			Colon:   token.Pos(6),
+			Variable: &ast.Variable{
+				Name:  &ast.Text{Value: "CFLAGS", ValuePos: token.Pos(8)},
+				Op:    token.APPEND_ASSIGN,
+				OpPos: token.Pos(15),
+				Value: []ast.Expr{&ast.Text{Value: "-g", ValuePos: token.Pos(18)}},
+			},
+		}))
+	})
+
+	It("should Parse a target-specific variable without separating spaces", func() {
+		buf := bytes.NewBufferString("debug: CFLAGS+=-g")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.TargetVariable{
+			Targets: []ast.Expr{&ast.Text{Value: "debug", ValuePos: token.Pos(1)}},
+			Tok:     token.COLON,
+			Colon:   token.Pos(6),
+			Variable: &ast.Variable{
+				Name:  &ast.Text{Value: "CFLAGS", ValuePos: token.Pos(8)},
+				Op:    token.APPEND_ASSIGN,
+				OpPos: token.Pos(14),
+				Value: []ast.Expr{&ast.Text{Value: "-g", ValuePos: token.Pos(16)}},
+			},
+		}))
+	})
+
+	DescribeTable("should Parse a pattern-specific variable with a modifier",
+		Entry(nil, "%.o: override EXTRA := 1", token.OVERRIDE),
+		Entry(nil, "%.o: export EXTRA := 1", token.EXPORT),
+		Entry(nil, "%.o: private EXTRA := 1", token.PRIVATE),
+		func(input string, mod token.Token) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			f, err := p.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			name := 7 + len(mod.String())
+			Expect(f.Contents).To(ConsistOf(&ast.TargetVariable{
+				Targets: []ast.Expr{&ast.Text{Value: "%.o", ValuePos: token.Pos(1)}},
+				Tok:     token.COLON,
+				Colon:   token.Pos(4),
+				Variable: &ast.Variable{
+					Modifiers: []*ast.Modifier{{Tok: mod, TokPos: token.Pos(6)}},
+					Name:      &ast.Text{Value: "EXTRA", ValuePos: token.Pos(name)},
+					Op:        token.SIMPLE_ASSIGN,
+					OpPos:     token.Pos(name + 6),
+					Value:     []ast.Expr{&ast.Text{Value: "1", ValuePos: token.Pos(name + 9)}},
+				},
+			}))
+		},
+	)
+
+	It("should Parse a target-specific variable with multiple modifiers", func() {
+		buf := bytes.NewBufferString("a b: override export X =")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.TargetVariable{
+			Targets: []ast.Expr{
+				&ast.Text{Value: "a", ValuePos: token.Pos(1)},
+				&ast.Text{Value: "b", ValuePos: token.Pos(3)},
+			},
+			Tok:   token.COLON,
+			Colon: token.Pos(4),
+			Variable: &ast.Variable{
+				Modifiers: []*ast.Modifier{
+					{Tok: token.OVERRIDE, TokPos: token.Pos(6)},
+					{Tok: token.EXPORT, TokPos: token.Pos(15)},
+				},
+				Name:  &ast.Text{Value: "X", ValuePos: token.Pos(22)},
+				Op:    token.RECURSIVE_ASSIGN,
+				OpPos: token.Pos(24),
+			},
+		}))
+	})
+
+	It("should Parse a double-colon target-specific variable", func() {
+		buf := bytes.NewBufferString("a:: X = 1")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.TargetVariable{
+			Targets: []ast.Expr{&ast.Text{Value: "a", ValuePos: token.Pos(1)}},
+			Tok:     token.DOUBLE_COLON,
+			Colon:   token.Pos(2),
+			Variable: &ast.Variable{
+				Name:  &ast.Text{Value: "X", ValuePos: token.Pos(5)},
+				Op:    token.RECURSIVE_ASSIGN,
+				OpPos: token.Pos(7),
+				Value: []ast.Expr{&ast.Text{Value: "1", ValuePos: token.Pos(9)}},
+			},
+		}))
+	})
+
+	It("should error when a target-specific modifier has no assignment", func() {
+		buf := bytes.NewBufferString("a: export X")
+		p := parser.New(buf, file)
+
+		_, err := p.ParseFile()
+
+		Expect(err).To(MatchError("test:1:12: expected assignment operator, found 'EOF'"))
+	})
+
+	It("should Parse a define directive", func() {
+		buf := bytes.NewBufferString("define FOO\nfoo  bar\n\tbaz\nendef")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.DefineDir{
+			Define: token.Pos(1),
+			Name: &ast.Text{
+				Value:    "FOO",
+				ValuePos: token.Pos(8),
+			},
+			Op: token.ILLEGAL,
+			Body: []*ast.Text{
+				{Value: "foo  bar", ValuePos: token.Pos(12)},
+				{Value: "\tbaz", ValuePos: token.Pos(21)},
+			},
+			Endef: token.Pos(26),
+		}))
+	})
+
+	DescribeTable("should Parse a define directive with an assignment operator",
+		Entry(nil, "define FOO =\nbar\nendef", token.RECURSIVE_ASSIGN),
+		Entry(nil, "define FOO :=\nbar\nendef", token.SIMPLE_ASSIGN),
+		Entry(nil, "define FOO ::=\nbar\nendef", token.POSIX_ASSIGN),
+		Entry(nil, "define FOO :::=\nbar\nendef", token.IMMEDIATE_ASSIGN),
+		Entry(nil, "define FOO ?=\nbar\nendef", token.IFNDEF_ASSIGN),
+		Entry(nil, "define FOO !=\nbar\nendef", token.SHELL_ASSIGN),
+		Entry(nil, "define FOO +=\nbar\nendef", token.APPEND_ASSIGN),
+		func(input string, op token.Token) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			f, err := p.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			start := 13 + len(op.String())
+			Expect(f.Contents).To(ConsistOf(&ast.DefineDir{
+				Define: token.Pos(1),
+				Name: &ast.Text{
+					Value:    "FOO",
+					ValuePos: token.Pos(8),
+				},
+				Op:    op,
+				OpPos: token.Pos(12),
+				Body: []*ast.Text{{
+					Value:    "bar",
+					ValuePos: token.Pos(start),
+				}},
+				Endef: token.Pos(start + 4),
+			}))
+		},
+	)
+
+	It("should Parse an empty define directive", func() {
+		buf := bytes.NewBufferString("define FOO\nendef")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.DefineDir{
+			Define: token.Pos(1),
+			Name: &ast.Text{
+				Value:    "FOO",
+				ValuePos: token.Pos(8),
+			},
+			Op:    token.ILLEGAL,
+			Endef: token.Pos(12),
+		}))
+	})
+
+	It("should Parse a define directive with a trailing comment", func() {
+		buf := bytes.NewBufferString("define FOO\nendef # comment")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.DefineDir{
+			Define: token.Pos(1),
+			Name: &ast.Text{
+				Value:    "FOO",
+				ValuePos: token.Pos(8),
+			},
+			Op:    token.ILLEGAL,
+			Endef: token.Pos(12),
+			Comment: &ast.Comment{
+				Pound: token.Pos(18),
+				Text:  "comment",
+			},
+		}))
+	})
+
+	It("should Parse a nested define directive as body text", func() {
+		buf := bytes.NewBufferString("define FOO\ndefine BAR\nendef\nendef")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.DefineDir{
+			Define: token.Pos(1),
+			Name: &ast.Text{
+				Value:    "FOO",
+				ValuePos: token.Pos(8),
+			},
+			Op: token.ILLEGAL,
+			Body: []*ast.Text{
+				{Value: "define BAR", ValuePos: token.Pos(12)},
+				{Value: "endef", ValuePos: token.Pos(23)},
+			},
+			Endef: token.Pos(29),
+		}))
+	})
+
+	It("should error when a define directive has no endef", func() {
+		buf := bytes.NewBufferString("define FOO\nbar")
+		p := parser.New(buf, file)
+
+		_, err := p.ParseFile()
+
+		Expect(err).To(MatchError("test:2:4: expected 'endef', found 'EOF'"))
+	})
+
+	DescribeTable("should Parse an include directive",
+		Entry(nil, "include foo.mk", token.INCLUDE),
+		Entry(nil, "-include foo.mk", token.DASH_INCLUDE),
+		Entry(nil, "sinclude foo.mk", token.SINCLUDE),
+		func(input string, tok token.Token) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			f, err := p.ParseFile()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Contents).To(ConsistOf(&ast.IncludeDir{
+				Tok:    tok,
+				TokPos: token.Pos(1),
+				Files: []ast.Expr{&ast.Text{
+					Value:    "foo.mk",
+					ValuePos: token.Pos(len(tok.String()) + 2),
+				}},
+			}))
+		},
+	)
+
+	It("should Parse an include directive with multiple files", func() {
+		buf := bytes.NewBufferString("include foo.mk $(BAR)\ninclude")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(HaveExactElements(
+			&ast.IncludeDir{
+				Tok:    token.INCLUDE,
+				TokPos: token.Pos(1),
+				Files: []ast.Expr{
+					&ast.Text{Value: "foo.mk", ValuePos: token.Pos(9)},
+					&ast.VarRef{
+						Dollar:   token.Pos(16),
+						Open:     token.LPAREN,
+						Name:     []ast.Expr{&ast.Text{Value: "BAR", ValuePos: token.Pos(18)}},
+						Close:    token.RPAREN,
+						ClosePos: token.Pos(21),
+					},
+				},
+			},
+			&ast.IncludeDir{
+				Tok:    token.INCLUDE,
+				TokPos: token.Pos(23),
+			},
+		))
+	})
+
+	It("should Parse an include directive with a trailing comment", func() {
+		buf := bytes.NewBufferString("include foo.mk # comment")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.IncludeDir{
+			Tok:    token.INCLUDE,
+			TokPos: token.Pos(1),
+			Files: []ast.Expr{&ast.Text{
+				Value:    "foo.mk",
+				ValuePos: token.Pos(9),
+			}},
+			Comment: &ast.Comment{
+				Pound: token.Pos(16),
+				Text:  "comment",
+			},
+		}))
+	})
+
+	It("should Parse a function call", func() {
+		buf := bytes.NewBufferString("OBJS := $(patsubst %.c,%.o,$(SRCS))")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Name:  &ast.Text{Value: "OBJS", ValuePos: token.Pos(1)},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(6),
+			Value: []ast.Expr{&ast.FuncCall{
+				Dollar:  token.Pos(9),
+				Open:    token.LPAREN,
+				Name:    token.PATSUBST,
+				NamePos: token.Pos(11),
+				Args: [][]ast.Expr{
+					{&ast.Text{Value: "%.c", ValuePos: token.Pos(20)}},
+					{&ast.Text{Value: "%.o", ValuePos: token.Pos(24)}},
+					{&ast.VarRef{
+						Dollar:   token.Pos(28),
+						Open:     token.LPAREN,
+						Name:     []ast.Expr{&ast.Text{Value: "SRCS", ValuePos: token.Pos(30)}},
+						Close:    token.RPAREN,
+						ClosePos: token.Pos(34),
+					}},
+				},
+				Commas:   []token.Pos{23, 27},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(35),
+			}},
+		}))
+	})
+
+	DescribeTable("should Parse a function call with delimiters",
+		Entry(nil, "X := $(wildcard *.go)", token.LPAREN, token.RPAREN),
+		Entry(nil, "X := ${wildcard *.go}", token.LBRACE, token.RBRACE),
+		func(input string, open, close token.Token) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			f, err := p.ParseFile()
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(f.Contents).To(ConsistOf(&ast.Variable{
-				Name: &ast.Text{
-					Value:    "VAR",
-					ValuePos: token.Pos(1),
-				},
-				Op:    op,
-				OpPos: token.Pos(5),
-				Value: nil,
+				Name:  &ast.Text{Value: "X", ValuePos: token.Pos(1)},
+				Op:    token.SIMPLE_ASSIGN,
+				OpPos: token.Pos(3),
+				Value: []ast.Expr{&ast.FuncCall{
+					Dollar:   token.Pos(6),
+					Open:     open,
+					Name:     token.WILDCARD,
+					NamePos:  token.Pos(8),
+					Args:     [][]ast.Expr{{&ast.Text{Value: "*.go", ValuePos: token.Pos(17)}}},
+					Close:    close,
+					ClosePos: token.Pos(21),
+				}},
 			}))
 		},
-		Entry(nil, "VAR :=", token.SIMPLE_ASSIGN),
-		Entry(nil, "VAR ::=", token.POSIX_ASSIGN),
-		Entry(nil, "VAR :::=", token.IMMEDIATE_ASSIGN),
-		Entry(nil, "VAR !=", token.SHELL_ASSIGN),
-		Entry(nil, "VAR ?=", token.IFNDEF_ASSIGN),
-		Entry(nil, "VAR =", token.RECURSIVE_ASSIGN),
 	)
 
+	It("should Parse a function call with empty arguments", func() {
+		buf := bytes.NewBufferString("X := $(if $(A),,)")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Name:  &ast.Text{Value: "X", ValuePos: token.Pos(1)},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(3),
+			Value: []ast.Expr{&ast.FuncCall{
+				Dollar:  token.Pos(6),
+				Open:    token.LPAREN,
+				Name:    token.IF,
+				NamePos: token.Pos(8),
+				Args: [][]ast.Expr{
+					{&ast.VarRef{
+						Dollar:   token.Pos(11),
+						Open:     token.LPAREN,
+						Name:     []ast.Expr{&ast.Text{Value: "A", ValuePos: token.Pos(13)}},
+						Close:    token.RPAREN,
+						ClosePos: token.Pos(14),
+					}},
+					nil,
+					nil,
+				},
+				Commas:   []token.Pos{15, 16},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(17),
+			}},
+		}))
+	})
+
+	It("should Parse nested parentheses in a function argument as text", func() {
+		buf := bytes.NewBufferString("X := $(shell echo (a,b))")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Name:  &ast.Text{Value: "X", ValuePos: token.Pos(1)},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(3),
+			Value: []ast.Expr{&ast.FuncCall{
+				Dollar:  token.Pos(6),
+				Open:    token.LPAREN,
+				Name:    token.SHELL,
+				NamePos: token.Pos(8),
+				Args: [][]ast.Expr{{
+					&ast.Text{Value: "echo", ValuePos: token.Pos(14)},
+					&ast.Text{Value: "(", ValuePos: token.Pos(19)},
+					&ast.Text{Value: "a", ValuePos: token.Pos(20)},
+					&ast.Text{Value: ",", ValuePos: token.Pos(21)},
+					&ast.Text{Value: "b", ValuePos: token.Pos(22)},
+					&ast.Text{Value: ")", ValuePos: token.Pos(23)},
+				}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(24),
+			}},
+		}))
+	})
+
+	It("should Parse '#' in a function argument as text", func() {
+		buf := bytes.NewBufferString("X := $(shell echo a # b) c # d")
+		p := parser.New(buf, file, parser.WithMode(parser.ParseComments))
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Variable{
+			Name:  &ast.Text{Value: "X", ValuePos: token.Pos(1)},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(3),
+			Value: []ast.Expr{
+				&ast.FuncCall{
+					Dollar:  token.Pos(6),
+					Open:    token.LPAREN,
+					Name:    token.SHELL,
+					NamePos: token.Pos(8),
+					Args: [][]ast.Expr{{
+						&ast.Text{Value: "echo", ValuePos: token.Pos(14)},
+						&ast.Text{Value: "a", ValuePos: token.Pos(19)},
+						&ast.Text{Value: "#", ValuePos: token.Pos(21)},
+						&ast.Text{Value: "b", ValuePos: token.Pos(23)},
+					}},
+					Close:    token.RPAREN,
+					ClosePos: token.Pos(24),
+				},
+				&ast.Text{Value: "c", ValuePos: token.Pos(26)},
+			},
+			Comment: &ast.Comment{Pound: token.Pos(28), Text: "d"},
+		}))
+	})
+
+	It("should Parse a function name without arguments as a variable reference", func() {
+		buf := bytes.NewBufferString("$(dir):")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:   token.COLON,
+			Colon: token.Pos(7),
+			Targets: []ast.Expr{&ast.VarRef{
+				Dollar:   token.Pos(1),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "dir", ValuePos: token.Pos(3)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(6),
+			}},
+			PreReqs:      []ast.Expr{},
+			OrderPreReqs: []ast.Expr{},
+			Recipes:      []ast.Obj{},
+		}))
+	})
+
+	It("should Parse function names outside of a reference as text", func() {
+		buf := bytes.NewBufferString("info: dir")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.Rule{
+			Tok:          token.COLON,
+			Colon:        token.Pos(5),
+			Targets:      []ast.Expr{&ast.Text{Value: "info", ValuePos: token.Pos(1)}},
+			PreReqs:      []ast.Expr{&ast.Text{Value: "dir", ValuePos: token.Pos(7)}},
+			OrderPreReqs: []ast.Expr{},
+			Recipes:      []ast.Obj{},
+		}))
+	})
+
+	It("should error when a function call has no closing token", func() {
+		buf := bytes.NewBufferString("X := $(subst a,b")
+		p := parser.New(buf, file)
+
+		_, err := p.ParseFile()
+
+		Expect(err).To(MatchError("test:1:17: expected ')', found 'EOF'"))
+	})
+
 	It("should Parse an ifeq conditional directive", func() {
 		buf := bytes.NewBufferString("ifeq (baz, bin)\nendif")
 		p := parser.New(buf, file)
@@ -556,15 +2450,15 @@ var _ = Describe("Parser", func() {
 				Tok:    token.IFEQ,
 				TokPos: token.Pos(1),
 				Open:   token.Pos(6),
-				Arg1: &ast.Text{
+				Arg1: []ast.Expr{&ast.Text{
 					Value:    "baz",
 					ValuePos: token.Pos(7),
-				},
+				}},
 				Comma: token.Pos(10),
-				Arg2: &ast.Text{
+				Arg2: []ast.Expr{&ast.Text{
 					Value:    "bin",
 					ValuePos: token.Pos(12),
-				},
+				}},
 				Close: token.Pos(15),
 			},
 			Endif: token.Pos(17),
@@ -583,15 +2477,15 @@ var _ = Describe("Parser", func() {
 				Tok:    token.IFNEQ,
 				TokPos: token.Pos(1),
 				Open:   token.Pos(7),
-				Arg1: &ast.Text{
+				Arg1: []ast.Expr{&ast.Text{
 					Value:    "baz",
 					ValuePos: token.Pos(8),
-				},
+				}},
 				Comma: token.Pos(11),
-				Arg2: &ast.Text{
+				Arg2: []ast.Expr{&ast.Text{
 					Value:    "bin",
 					ValuePos: token.Pos(13),
-				},
+				}},
 				Close: token.Pos(16),
 			},
 			Endif: token.Pos(18),
@@ -609,34 +2503,165 @@ var _ = Describe("Parser", func() {
 			Directive: &ast.IfeqDir{
 				Tok:    token.IFEQ,
 				TokPos: token.Pos(1),
-				Arg1: &ast.QuotedExpr{
+				Arg1: []ast.Expr{&ast.QuotedExpr{
 					Quote: token.APOS,
 					Open:  token.Pos(6),
-					Value: &ast.Text{
+					Value: []ast.Expr{&ast.Text{
 						Value:    "baz",
 						ValuePos: token.Pos(7),
-					},
+					}},
 					Close: token.Pos(10),
-				},
-				Arg2: &ast.QuotedExpr{
+				}},
+				Arg2: []ast.Expr{&ast.QuotedExpr{
 					Quote: token.QUOTE,
 					Open:  token.Pos(12),
-					Value: &ast.Text{
+					Value: []ast.Expr{&ast.Text{
 						Value:    "bin",
 						ValuePos: token.Pos(13),
+					}},
+					Close: token.Pos(16),
+				}},
+			},
+			Endif: token.Pos(18),
+		}))
+	})
+
+	It("should Parse an ifeq conditional directive with an empty argument", func() {
+		buf := bytes.NewBufferString("ifeq ($(CI),)\nendif")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.IfBlock{
+			Directive: &ast.IfeqDir{
+				Tok:    token.IFEQ,
+				TokPos: token.Pos(1),
+				Open:   token.Pos(6),
+				Arg1: []ast.Expr{&ast.VarRef{
+					Dollar:   token.Pos(7),
+					Open:     token.LPAREN,
+					Name:     []ast.Expr{&ast.Text{Value: "CI", ValuePos: token.Pos(9)}},
+					Close:    token.RPAREN,
+					ClosePos: token.Pos(11),
+				}},
+				Comma: token.Pos(12),
+				Close: token.Pos(13),
+			},
+			Endif: token.Pos(15),
+		}))
+	})
+
+	It("should Parse an ifeq conditional directive with a function call argument", func() {
+		buf := bytes.NewBufferString("ifeq ($(strip $(FOO)),)\nendif")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.IfBlock{
+			Directive: &ast.IfeqDir{
+				Tok:    token.IFEQ,
+				TokPos: token.Pos(1),
+				Open:   token.Pos(6),
+				Arg1: []ast.Expr{&ast.FuncCall{
+					Dollar:  token.Pos(7),
+					Open:    token.LPAREN,
+					Name:    token.STRIP,
+					NamePos: token.Pos(9),
+					Args: [][]ast.Expr{{&ast.VarRef{
+						Dollar:   token.Pos(15),
+						Open:     token.LPAREN,
+						Name:     []ast.Expr{&ast.Text{Value: "FOO", ValuePos: token.Pos(17)}},
+						Close:    token.RPAREN,
+						ClosePos: token.Pos(20),
+					}}},
+					Close:    token.RPAREN,
+					ClosePos: token.Pos(21),
+				}},
+				Comma: token.Pos(22),
+				Close: token.Pos(23),
+			},
+			Endif: token.Pos(25),
+		}))
+	})
+
+	It("should Parse an ifeq conditional directive with references in quotes", func() {
+		buf := bytes.NewBufferString("ifeq \"$(A) $(B)\" \"\"\nendif")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.IfBlock{
+			Directive: &ast.IfeqDir{
+				Tok:    token.IFEQ,
+				TokPos: token.Pos(1),
+				Arg1: []ast.Expr{&ast.QuotedExpr{
+					Quote: token.QUOTE,
+					Open:  token.Pos(6),
+					Value: []ast.Expr{
+						&ast.VarRef{
+							Dollar:   token.Pos(7),
+							Open:     token.LPAREN,
+							Name:     []ast.Expr{&ast.Text{Value: "A", ValuePos: token.Pos(9)}},
+							Close:    token.RPAREN,
+							ClosePos: token.Pos(10),
+						},
+						&ast.VarRef{
+							Dollar:   token.Pos(12),
+							Open:     token.LPAREN,
+							Name:     []ast.Expr{&ast.Text{Value: "B", ValuePos: token.Pos(14)}},
+							Close:    token.RPAREN,
+							ClosePos: token.Pos(15),
+						},
 					},
 					Close: token.Pos(16),
+				}},
+				Arg2: []ast.Expr{&ast.QuotedExpr{
+					Quote: token.QUOTE,
+					Open:  token.Pos(18),
+					Close: token.Pos(19),
+				}},
+			},
+			Endif: token.Pos(21),
+		}))
+	})
+
+	It("should Parse parentheses and commas in ifeq arguments as text", func() {
+		buf := bytes.NewBufferString("ifeq ((a),b,c)\nendif")
+		p := parser.New(buf, file)
+
+		f, err := p.ParseFile()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Contents).To(ConsistOf(&ast.IfBlock{
+			Directive: &ast.IfeqDir{
+				Tok:    token.IFEQ,
+				TokPos: token.Pos(1),
+				Open:   token.Pos(6),
+				Arg1: []ast.Expr{
+					&ast.Text{Value: "(", ValuePos: token.Pos(7)},
+					&ast.Text{Value: "a", ValuePos: token.Pos(8)},
+					&ast.Text{Value: ")", ValuePos: token.Pos(9)},
 				},
+				Comma: token.Pos(10),
+				Arg2: []ast.Expr{
+					&ast.Text{Value: "b", ValuePos: token.Pos(11)},
+					&ast.Text{Value: ",", ValuePos: token.Pos(12)},
+					&ast.Text{Value: "c", ValuePos: token.Pos(13)},
+				},
+				Close: token.Pos(14),
 			},
-			Endif: token.Pos(18),
+			Endif: token.Pos(16),
 		}))
 	})
 
-	DescribeTable("should error when quotes are mismatched",
-		Entry(nil, "ifeq 'baz\" \"bin\"\nendif", "test:1:10: expected ''', found '\"'"),
-		Entry(nil, "ifeq \"baz' \"bin\"\nendif", "test:1:10: expected '\"', found '''"),
-		Entry(nil, "ifeq 'baz' \"bin'\nendif", "test:1:16: expected '\"', found '''"),
-		Entry(nil, "ifeq 'baz' 'bin\"\nendif", "test:1:16: expected ''', found '\"'"),
+	DescribeTable("should error on unbalanced quotes",
+		Entry(nil, "ifeq \"baz\" 'bin\nendif", "test:1:16: expected ''', found '\n'"),
+		Entry(nil, "ifeq \"baz' \"bin\"\nendif", "test:1:13: expected one of ''', '\"', found bin"),
+		Entry(nil, "ifeq 'baz' \"bin'\nendif", "test:1:17: expected '\"', found '\n'"),
+		Entry(nil, "ifeq 'baz' 'bin\"\nendif", "test:1:17: expected ''', found '\n'"),
 		func(input, msg string) {
 			buf := bytes.NewBufferString(input)
 			p := parser.New(buf, file)
@@ -714,15 +2739,15 @@ var _ = Describe("Parser", func() {
 				Tok:    token.IFEQ,
 				TokPos: token.Pos(1),
 				Open:   token.Pos(6),
-				Arg1: &ast.Text{
+				Arg1: []ast.Expr{&ast.Text{
 					Value:    "baz",
 					ValuePos: token.Pos(7),
-				},
+				}},
 				Comma: token.Pos(10),
-				Arg2: &ast.Text{
+				Arg2: []ast.Expr{&ast.Text{
 					Value:    "bin",
 					ValuePos: token.Pos(12),
-				},
+				}},
 				Close: token.Pos(15),
 			},
 			Text: []ast.Obj{&ast.Rule{
@@ -730,10 +2755,11 @@ var _ = Describe("Parser", func() {
 					Value:    "target",
 					ValuePos: token.Pos(17),
 				}},
+				Tok:          token.COLON,
 				Colon:        token.Pos(23),
 				PreReqs:      []ast.Expr{},
 				OrderPreReqs: []ast.Expr{},
-				Recipes:      []*ast.Recipe{},
+				Recipes:      []ast.Obj{},
 			}},
 			Endif: token.Pos(25),
 		}))
@@ -751,15 +2777,15 @@ var _ = Describe("Parser", func() {
 				Tok:    token.IFEQ,
 				TokPos: token.Pos(1),
 				Open:   token.Pos(6),
-				Arg1: &ast.Text{
+				Arg1: []ast.Expr{&ast.Text{
 					Value:    "baz",
 					ValuePos: token.Pos(7),
-				},
+				}},
 				Comma: token.Pos(10),
-				Arg2: &ast.Text{
+				Arg2: []ast.Expr{&ast.Text{
 					Value:    "bin",
 					ValuePos: token.Pos(12),
-				},
+				}},
 				Close: token.Pos(15),
 			},
 			Else: []*ast.ElseBlock{{
@@ -781,15 +2807,15 @@ var _ = Describe("Parser", func() {
 				Tok:    token.IFEQ,
 				TokPos: token.Pos(1),
 				Open:   token.Pos(6),
-				Arg1: &ast.Text{
+				Arg1: []ast.Expr{&ast.Text{
 					Value:    "baz",
 					ValuePos: token.Pos(7),
-				},
+				}},
 				Comma: token.Pos(10),
-				Arg2: &ast.Text{
+				Arg2: []ast.Expr{&ast.Text{
 					Value:    "bin",
 					ValuePos: token.Pos(12),
-				},
+				}},
 				Close: token.Pos(15),
 			},
 			Else: []*ast.ElseBlock{{
@@ -799,10 +2825,11 @@ var _ = Describe("Parser", func() {
 						Value:    "target",
 						ValuePos: token.Pos(22),
 					}},
+					Tok:          token.COLON,
 					Colon:        token.Pos(28),
 					PreReqs:      []ast.Expr{},
 					OrderPreReqs: []ast.Expr{},
-					Recipes:      []*ast.Recipe{},
+					Recipes:      []ast.Obj{},
 				}},
 			}},
 			Endif: token.Pos(30),
@@ -821,15 +2848,15 @@ var _ = Describe("Parser", func() {
 				Tok:    token.IFEQ,
 				TokPos: token.Pos(1),
 				Open:   token.Pos(6),
-				Arg1: &ast.Text{
+				Arg1: []ast.Expr{&ast.Text{
 					Value:    "baz",
 					ValuePos: token.Pos(7),
-				},
+				}},
 				Comma: token.Pos(10),
-				Arg2: &ast.Text{
+				Arg2: []ast.Expr{&ast.Text{
 					Value:    "bin",
 					ValuePos: token.Pos(12),
-				},
+				}},
 				Close: token.Pos(15),
 			},
 			Else: []*ast.ElseBlock{{
@@ -838,15 +2865,15 @@ var _ = Describe("Parser", func() {
 					Tok:    token.IFEQ,
 					TokPos: token.Pos(22),
 					Open:   token.Pos(27),
-					Arg1: &ast.Text{
+					Arg1: []ast.Expr{&ast.Text{
 						Value:    "baz",
 						ValuePos: token.Pos(28),
-					},
+					}},
 					Comma: token.Pos(31),
-					Arg2: &ast.Text{
+					Arg2: []ast.Expr{&ast.Text{
 						Value:    "bin",
 						ValuePos: token.Pos(33),
-					},
+					}},
 					Close: token.Pos(36),
 				},
 				Text: []ast.Obj{&ast.Rule{
@@ -854,10 +2881,11 @@ var _ = Describe("Parser", func() {
 						Value:    "target",
 						ValuePos: token.Pos(38),
 					}},
+					Tok:          token.COLON,
 					Colon:        token.Pos(44),
 					PreReqs:      []ast.Expr{},
 					OrderPreReqs: []ast.Expr{},
-					Recipes:      []*ast.Recipe{},
+					Recipes:      []ast.Obj{},
 				}},
 			}},
 			Endif: token.Pos(46),
@@ -898,15 +2926,15 @@ endif
 				Tok:    token.IFEQ,
 				TokPos: token.Pos(1),
 				Open:   token.Pos(6),
-				Arg1: &ast.Text{
+				Arg1: []ast.Expr{&ast.Text{
 					Value:    "baz",
 					ValuePos: token.Pos(7),
-				},
+				}},
 				Comma: token.Pos(10),
-				Arg2: &ast.Text{
+				Arg2: []ast.Expr{&ast.Text{
 					Value:    "bin",
 					ValuePos: token.Pos(12),
-				},
+				}},
 				Close: token.Pos(15),
 			},
 			Text: []ast.Obj{&ast.Variable{
@@ -965,4 +2993,156 @@ endif
 
 		Expect(err).To(MatchError("test:1:13: variable may have only one name"))
 	})
+
+	DescribeTable("should error when a rule has no targets",
+		Entry(nil, ": foo\n", "test:1:1: expected target, found ':'"),
+		Entry(nil, "# c\n:: foo\n", "test:2:1: expected target, found '::'"),
+		func(input, msg string) {
+			buf := bytes.NewBufferString(input)
+			p := parser.New(buf, file)
+
+			f, err := p.ParseFile()
+
+			Expect(err).To(MatchError(msg))
+			_, ok := f.Contents[len(f.Contents)-1].(*ast.BadObj)
+			Expect(ok).To(BeTrue())
+		},
+	)
+})
+
+var _ = Describe("ParseExpr", func() {
+	It("should parse a variable reference", func() {
+		e, err := parser.ParseExpr("$(FOO)")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(e).To(Equal(&ast.VarRef{
+			Dollar: token.Pos(1),
+			Open:   token.LPAREN,
+			Name: []ast.Expr{&ast.Text{
+				Value:    "FOO",
+				ValuePos: token.Pos(3),
+			}},
+			Close:    token.RPAREN,
+			ClosePos: token.Pos(6),
+		}))
+	})
+
+	It("should allow trailing whitespace", func() {
+		e, err := parser.ParseExpr("prereq\n")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(e).To(Equal(&ast.Text{
+			Value:    "prereq",
+			ValuePos: token.Pos(1),
+		}))
+	})
+
+	It("should error when trailing input remains", func() {
+		_, err := parser.ParseExpr("$(FOO) bar")
+
+		Expect(err).To(MatchError("1:8: expected 'EOF', found bar"))
+	})
+
+	It("should position nodes in the given file set", func() {
+		fset := token.NewFileSet()
+		fset.AddFile("other.mk", -1, 10)
+
+		e, err := parser.ParseExprFrom(fset, "test.mk", "prereq", 0)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(e.Pos()).To(Equal(token.Pos(12)))
+		Expect(fset.Position(e.Pos()).String()).To(Equal("test.mk:1:1"))
+	})
+})
+
+var _ = Describe("ParseExprList", func() {
+	It("should parse a word starting with a variable reference", func() {
+		l, err := parser.ParseExprList("$(OBJDIR)/foo.o")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l).To(Equal([]ast.Expr{
+			&ast.VarRef{
+				Dollar:   token.Pos(1),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "OBJDIR", ValuePos: token.Pos(3)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(9),
+			},
+			&ast.Text{Value: "/foo.o", ValuePos: token.Pos(10)},
+		}))
+	})
+
+	It("should parse a word containing a variable reference", func() {
+		l, err := parser.ParseExprList("lib$(X).a")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l).To(Equal([]ast.Expr{
+			&ast.Text{Value: "lib", ValuePos: token.Pos(1)},
+			&ast.VarRef{
+				Dollar:   token.Pos(4),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "X", ValuePos: token.Pos(6)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(7),
+			},
+			&ast.Text{Value: ".a", ValuePos: token.Pos(8)},
+		}))
+	})
+
+	It("should parse whitespace separated words", func() {
+		l, err := parser.ParseExprList("foo.o $(BAR)\n")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l).To(HaveLen(2))
+		Expect(l[0]).To(Equal(&ast.Text{Value: "foo.o", ValuePos: token.Pos(1)}))
+		Expect(l[1].Pos()).To(Equal(token.Pos(7)))
+	})
+
+	It("should error when more lines remain", func() {
+		_, err := parser.ParseExprListFrom(token.NewFileSet(), "test.mk", "foo\nbar", 0)
+
+		Expect(err).To(MatchError("test.mk:2:1: expected 'EOF', found bar"))
+	})
+})
+
+var _ = Describe("ParseObj", func() {
+	It("should parse a variable", func() {
+		o, err := parser.ParseObj("VAR := value\n")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(o).To(Equal(&ast.Variable{
+			Name: &ast.Text{
+				Value:    "VAR",
+				ValuePos: token.Pos(1),
+			},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(5),
+			Value: []ast.Expr{&ast.Text{
+				Value:    "value",
+				ValuePos: token.Pos(8),
+			}},
+		}))
+	})
+
+	It("should parse a rule with recipes", func() {
+		o, err := parser.ParseObj("target: prereq\n\trecipe\n")
+
+		Expect(err).NotTo(HaveOccurred())
+		r, ok := o.(*ast.Rule)
+		Expect(ok).To(BeTrue())
+		Expect(r.Recipes).To(HaveLen(1))
+	})
+
+	It("should error when trailing input remains", func() {
+		_, err := parser.ParseObjFrom(token.NewFileSet(), "test.mk", "target:\nother:\n", 0)
+
+		Expect(err).To(MatchError("test.mk:2:1: expected 'EOF', found other"))
+	})
+
+	It("should return a bad object for invalid input", func() {
+		o, err := parser.ParseObj("foo")
+
+		Expect(err).To(MatchError("1:4: expected ':' or assignment, found 'EOF'"))
+		Expect(o).To(Equal(&ast.BadObj{From: token.Pos(1), To: token.Pos(4)}))
+	})
 })
@@ -3,6 +3,8 @@ package printer
 import (
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 
 	"github.com/unmango/go-make/ast"
 	"github.com/unmango/go-make/token"
@@ -10,9 +12,10 @@ import (
 )
 
 type printer struct {
-	f   *token.File
-	out []byte
-	pos token.Position
+	f    *token.File
+	out  []byte
+	pos  token.Position
+	tabs []token.Pos // positions of tabs indenting lines other than recipe lines
 }
 
 type Op func(*printer)
@@ -33,6 +36,19 @@ func (p *printer) writeLine() {
 	p.pos.Offset++
 }
 
+// endLine writes the trailing comment c, if any, and a line break
+// unless l ends in a continuation that has already written one.
+func (p *printer) endLine(l []ast.Expr, c *ast.Comment) {
+	p.lineComment(c)
+	if c == nil && len(l) > 0 {
+		if _, ok := l[len(l)-1].(*ast.Continuation); ok {
+			return
+		}
+	}
+
+	p.writeLine()
+}
+
 func (p *printer) fill(c byte, pos token.Pos) {
 	p.writeChar(c, int(pos)-(p.pos.Offset+1))
 }
@@ -42,7 +58,13 @@ func (p *printer) fillSpace(pos token.Pos) {
 }
 
 func (p *printer) fillLines(pos token.Pos) {
-	p.fill('\n', pos)
+	for i := token.Pos(p.pos.Offset + 1); i < pos; i++ {
+		if _, ok := slices.BinarySearch(p.tabs, i); ok {
+			p.writeChar('\t', 1)
+		} else {
+			p.writeLine()
+		}
+	}
 }
 
 func (p *printer) writeChar(r byte, n int) {
@@ -68,6 +90,14 @@ func (p *printer) writeString(pos token.Position, s string) {
 	p.pos.Column += len(s)
 }
 
+// skip advances the current position to pos without writing anything.
+func (p *printer) skip(pos token.Pos) {
+	if n := int(pos) - (p.pos.Offset + 1); n > 0 {
+		p.pos.Offset += n
+		p.pos.Column += n
+	}
+}
+
 func (p *printer) tok(pos token.Position, t token.Token) {
 	p.writeString(pos, t.String())
 }
@@ -79,14 +109,31 @@ func (p *printer) text(t *ast.Text) {
 
 func (p *printer) quotedExpr(e *ast.QuotedExpr) {
 	p.tok(p.posFor(e.Open), e.Quote)
-	p.expr(e.Value)
+	p.exprList(e.Value)
+	p.fillSpace(e.Close)
 	p.tok(p.posFor(e.Close), e.Quote)
 }
 
 func (p *printer) recipe(r *ast.Recipe) {
+	if r.Prefix == token.SEMI {
+		p.fillSpace(r.PrefixPos)
+	}
+
 	pos := p.posFor(r.PrefixPos)
-	p.tok(pos, r.Prefix)
-	p.expr(r)
+	if r.Prefix == token.TEXT {
+		p.writeString(pos, r.PrefixLit)
+	} else {
+		p.tok(pos, r.Prefix)
+	}
+	if r.Modifiers != "" {
+		p.fillSpace(r.ModifiersPos)
+		p.writeString(p.posFor(r.ModifiersPos), r.Modifiers)
+	}
+	if r.Exprs != nil {
+		p.exprList(r.Exprs)
+	} else {
+		p.expr(r)
+	}
 	p.writeLine()
 }
 
@@ -95,12 +142,52 @@ func (p *printer) varRef(v *ast.VarRef) {
 	if v.Open != token.ILLEGAL {
 		p.tok(p.pos, v.Open)
 	}
-	p.writeString(p.pos, v.Name)
-	if v.Close != token.ILLEGAL {
+	p.exprList(v.Name)
+	if v.ClosePos.IsValid() {
+		p.fillSpace(v.ClosePos)
+		p.tok(p.posFor(v.ClosePos), v.Close)
+	} else if v.Close != token.ILLEGAL {
 		p.tok(p.pos, v.Close)
 	}
 }
 
+func (p *printer) substRef(r *ast.SubstRef) {
+	p.tok(p.posFor(r.Dollar), token.DOLLAR)
+	p.tok(p.pos, r.Open)
+	for _, e := range r.Name {
+		p.expr(e)
+	}
+	p.fillSpace(r.Colon)
+	p.tok(p.posFor(r.Colon), token.COLON)
+	p.exprList(r.From)
+	p.fillSpace(r.Assign)
+	p.tok(p.posFor(r.Assign), token.RECURSIVE_ASSIGN)
+	p.exprList(r.To)
+	p.fillSpace(r.ClosePos)
+	p.tok(p.posFor(r.ClosePos), r.Close)
+}
+
+func (p *printer) funcCall(c *ast.FuncCall) {
+	p.tok(p.posFor(c.Dollar), token.DOLLAR)
+	p.tok(p.pos, c.Open)
+	p.tok(p.posFor(c.NamePos), c.Name)
+	for i, arg := range c.Args {
+		if i > 0 {
+			p.fillSpace(c.Commas[i-1])
+			p.tok(p.posFor(c.Commas[i-1]), token.COMMA)
+		}
+		p.exprList(arg)
+	}
+	p.fillSpace(c.ClosePos)
+	p.tok(p.posFor(c.ClosePos), c.Close)
+}
+
+func (p *printer) continuation(c *ast.Continuation) {
+	p.writeString(p.posFor(c.Pos()), c.Text)
+	p.pos.Line++
+	p.pos.Column = len(c.Text) - strings.LastIndexByte(c.Text, '\n')
+}
+
 func (p *printer) expr(expr ast.Expr) {
 	switch n := expr.(type) {
 	case *ast.Text:
@@ -111,6 +198,17 @@ func (p *printer) expr(expr ast.Expr) {
 		p.text(&n.Text)
 	case *ast.VarRef:
 		p.varRef(n)
+	case *ast.SubstRef:
+		p.substRef(n)
+	case *ast.FuncCall:
+		p.funcCall(n)
+	case *ast.Continuation:
+		p.continuation(n)
+	case *ast.Escape:
+		p.writeString(p.posFor(n.Dollar), n.String())
+	case *ast.BadExpr:
+		p.writeString(p.posFor(n.From), "BadExpr")
+		p.skip(n.To)
 	}
 }
 
@@ -133,9 +231,12 @@ func (p *printer) prereqList(l []ast.Expr) {
 	}
 }
 
-func (p *printer) recipeList(l []*ast.Recipe) {
-	for _, r := range l {
-		p.recipe(r)
+func (p *printer) recipeList(l []ast.Obj) {
+	for _, o := range l {
+		if r, ok := o.(*ast.Recipe); !ok || r.Prefix != token.SEMI {
+			p.fillLines(o.Pos())
+		}
+		p.obj(o)
 	}
 }
 
@@ -146,23 +247,36 @@ func (p *printer) rule(r *ast.Rule) {
 
 	p.targetList(r.Targets)
 	p.fillSpace(r.Colon)
-	p.tok(p.posFor(r.Colon), token.COLON)
+	if r.Tok == token.DOUBLE_COLON || r.Tok == token.AND_COLON {
+		p.tok(p.posFor(r.Colon), r.Tok)
+	} else {
+		p.tok(p.posFor(r.Colon), token.COLON)
+	}
+	if r.PatternColon.IsValid() {
+		p.exprList(r.Pattern)
+		p.fillSpace(r.PatternColon)
+		p.tok(p.posFor(r.PatternColon), token.COLON)
+	}
 	p.prereqList(r.PreReqs)
+	last := r.PreReqs
 	if r.Pipe.IsValid() {
 		p.fillSpace(r.Pipe)
 		p.tok(p.posFor(r.Pipe), token.PIPE)
+		last = r.OrderPreReqs
 	}
 	if len(r.OrderPreReqs) > 0 {
 		p.exprList(r.OrderPreReqs)
 	}
-	if len(r.Recipes) > 0 {
-		if r.Recipes[0].Prefix != token.SEMI {
-			p.writeLine()
-		}
-		p.recipeList(r.Recipes)
-	} else {
+	if len(r.Recipes) == 0 {
+		p.endLine(last, r.Comment)
+		return
+	}
+
+	p.lineComment(r.Comment)
+	if r, ok := r.Recipes[0].(*ast.Recipe); !ok || r.Prefix != token.SEMI {
 		p.writeLine()
 	}
+	p.recipeList(r.Recipes)
 }
 
 func (p *printer) comment(c *ast.Comment) {
@@ -171,6 +285,13 @@ func (p *printer) comment(c *ast.Comment) {
 	p.writeString(p.pos, c.Text)
 }
 
+func (p *printer) lineComment(c *ast.Comment) {
+	if c != nil {
+		p.fillSpace(c.Pound)
+		p.comment(c)
+	}
+}
+
 func (p *printer) commentGroup(g *ast.CommentGroup) {
 	if g == nil {
 		return
@@ -187,26 +308,24 @@ func (p *printer) ifeqDir(d *ast.IfeqDir) {
 	if d.Open.IsValid() {
 		p.fillSpace(d.Open)
 		p.tok(p.posFor(d.Open), token.LPAREN)
-		p.fillSpace(d.Arg1.Pos())
-		p.expr(d.Arg1)
+		p.exprList(d.Arg1)
 		p.fillSpace(d.Comma)
 		p.tok(p.posFor(d.Comma), token.COMMA)
-		p.fillSpace(d.Arg2.Pos())
-		p.expr(d.Arg2)
+		p.exprList(d.Arg2)
 		p.fillSpace(d.Close)
 		p.tok(p.posFor(d.Close), token.RPAREN)
 	} else {
-		p.fillSpace(d.Arg1.Pos())
-		p.expr(d.Arg1)
-		p.fillSpace(d.Arg2.Pos())
-		p.expr(d.Arg2)
+		p.exprList(d.Arg1)
+		p.exprList(d.Arg2)
 	}
+	p.lineComment(d.Comment)
 }
 
 func (p *printer) ifdefDir(d *ast.IfdefDir) {
 	p.tok(p.posFor(d.TokPos), d.Tok)
 	p.fillSpace(d.VarName.Pos())
 	p.expr(d.VarName)
+	p.lineComment(d.Comment)
 }
 
 func (p *printer) ifDir(d ast.IfDir) {
@@ -224,6 +343,7 @@ func (p *printer) elseBlock(b *ast.ElseBlock) {
 		p.fillSpace(b.Condition.Pos())
 		p.ifDir(b.Condition)
 	}
+	p.lineComment(b.Comment)
 	p.writeLine()
 	p.objList(b.Text)
 }
@@ -235,6 +355,89 @@ func (p *printer) ifBlock(b *ast.IfBlock) {
 		p.elseBlock(e)
 	}
 	p.tok(p.posFor(b.Endif), token.ENDIF)
+	p.lineComment(b.EndifComment)
+	p.writeLine()
+}
+
+func (p *printer) defineDir(d *ast.DefineDir) {
+	if len(d.Modifiers) > 0 {
+		p.modifiers(d.Modifiers)
+		p.fillSpace(d.Define)
+	}
+	p.tok(p.posFor(d.Define), token.DEFINE)
+	p.fillSpace(d.Name.Pos())
+	p.expr(d.Name)
+	if d.OpPos.IsValid() {
+		p.fillSpace(d.OpPos)
+		p.tok(p.posFor(d.OpPos), d.Op)
+	}
+	p.writeLine()
+	for _, l := range d.Body {
+		p.text(l)
+		p.writeLine()
+	}
+	p.fillSpace(d.Endef)
+	p.tok(p.posFor(d.Endef), token.ENDEF)
+	p.lineComment(d.Comment)
+	p.writeLine()
+}
+
+func (p *printer) includeDir(d *ast.IncludeDir) {
+	p.tok(p.posFor(d.TokPos), d.Tok)
+	if d.Files != nil {
+		p.exprList(d.Files)
+	}
+	p.endLine(d.Files, d.Comment)
+}
+
+func (p *printer) exportDir(d *ast.ExportDir) {
+	p.tok(p.posFor(d.Export), token.EXPORT)
+	if d.Names != nil {
+		p.exprList(d.Names)
+	}
+	p.endLine(d.Names, d.Comment)
+}
+
+func (p *printer) unexportDir(d *ast.UnexportDir) {
+	p.tok(p.posFor(d.Unexport), token.UNEXPORT)
+	if d.Names != nil {
+		p.exprList(d.Names)
+	}
+	p.endLine(d.Names, d.Comment)
+}
+
+func (p *printer) undefineDir(d *ast.UndefineDir) {
+	p.modifiers(d.Modifiers)
+	p.fillSpace(d.Undefine)
+	p.tok(p.posFor(d.Undefine), token.UNDEFINE)
+	p.fillSpace(d.Name.Pos())
+	p.expr(d.Name)
+	p.lineComment(d.Comment)
+	p.writeLine()
+}
+
+func (p *printer) vpathDir(d *ast.VpathDir) {
+	p.tok(p.posFor(d.Vpath), token.VPATH)
+	if d.Pattern != nil {
+		p.fillSpace(d.Pattern.Pos())
+		p.expr(d.Pattern)
+	}
+
+	colons := d.Colons
+	for _, e := range d.Directories {
+		for len(colons) > 0 && colons[0] < e.Pos() {
+			p.fillSpace(colons[0])
+			p.tok(p.posFor(colons[0]), token.COLON)
+			colons = colons[1:]
+		}
+		p.fillSpace(e.Pos())
+		p.expr(e)
+	}
+	for _, c := range colons {
+		p.fillSpace(c)
+		p.tok(p.posFor(c), token.COLON)
+	}
+	p.lineComment(d.Comment)
 	p.writeLine()
 }
 
@@ -242,6 +445,25 @@ func (p *printer) directive(d ast.Dir) {
 	switch n := d.(type) {
 	case *ast.IfBlock:
 		p.ifBlock(n)
+	case *ast.DefineDir:
+		p.defineDir(n)
+	case *ast.IncludeDir:
+		p.includeDir(n)
+	case *ast.ExportDir:
+		p.exportDir(n)
+	case *ast.UnexportDir:
+		p.unexportDir(n)
+	case *ast.UndefineDir:
+		p.undefineDir(n)
+	case *ast.VpathDir:
+		p.vpathDir(n)
+	}
+}
+
+func (p *printer) modifiers(l []*ast.Modifier) {
+	for _, m := range l {
+		p.fillSpace(m.TokPos)
+		p.tok(p.posFor(m.TokPos), m.Tok)
 	}
 }
 
@@ -250,13 +472,33 @@ func (p *printer) variable(v *ast.Variable) {
 		return
 	}
 
+	if len(v.Modifiers) > 0 {
+		p.modifiers(v.Modifiers)
+		p.fillSpace(v.Name.Pos())
+	}
 	p.expr(v.Name)
 	p.fillSpace(v.OpPos)
 	p.tok(p.posFor(v.OpPos), v.Op)
 	if v.Value != nil {
 		p.exprList(v.Value)
 	}
-	p.writeLine()
+	p.endLine(v.Value, v.Comment)
+}
+
+func (p *printer) targetVariable(v *ast.TargetVariable) {
+	if v == nil {
+		return
+	}
+
+	p.targetList(v.Targets)
+	p.fillSpace(v.Colon)
+	if v.Tok == token.DOUBLE_COLON || v.Tok == token.AND_COLON {
+		p.tok(p.posFor(v.Colon), v.Tok)
+	} else {
+		p.tok(p.posFor(v.Colon), token.COLON)
+	}
+	p.fillSpace(v.Variable.Pos())
+	p.variable(v.Variable)
 }
 
 func (p *printer) obj(o ast.Obj) {
@@ -265,10 +507,18 @@ func (p *printer) obj(o ast.Obj) {
 		p.directive(n)
 	case *ast.CommentGroup:
 		p.commentGroup(n)
+	case *ast.Recipe:
+		p.recipe(n)
 	case *ast.Rule:
 		p.rule(n)
 	case *ast.Variable:
 		p.variable(n)
+	case *ast.TargetVariable:
+		p.targetVariable(n)
+	case *ast.BadObj:
+		p.writeString(p.posFor(n.From), "BadObj")
+		p.skip(n.To)
+		p.writeLine()
 	}
 }
 
@@ -281,6 +531,7 @@ func (p *printer) objList(l []ast.Obj) {
 
 func (p *printer) file(f *ast.File) {
 	if f != nil {
+		p.tabs = f.Tabs
 		p.objList(f.Contents)
 	}
 }
@@ -25,6 +25,55 @@ var _ = Describe("Printer", func() {
 				},
 				"target:\n",
 			),
+			Entry("double-colon target",
+				&ast.Rule{
+					Tok:   token.DOUBLE_COLON,
+					Colon: token.Pos(6),
+					Targets: []ast.Expr{&ast.Text{
+						Value:    "clean",
+						ValuePos: token.Pos(1),
+					}},
+					PreReqs: []ast.Expr{&ast.Text{
+						Value:    "prereq",
+						ValuePos: token.Pos(9),
+					}},
+				},
+				"clean:: prereq\n",
+			),
+			Entry("grouped targets",
+				&ast.Rule{
+					Tok:   token.AND_COLON,
+					Colon: token.Pos(13),
+					Targets: []ast.Expr{
+						&ast.Text{Value: "foo.h", ValuePos: token.Pos(1)},
+						&ast.Text{Value: "foo.c", ValuePos: token.Pos(7)},
+					},
+					PreReqs: []ast.Expr{&ast.Text{
+						Value:    "foo.y",
+						ValuePos: token.Pos(16),
+					}},
+				},
+				"foo.h foo.c &: foo.y\n",
+			),
+			Entry("static pattern rule",
+				&ast.Rule{
+					Colon: token.Pos(8),
+					Targets: []ast.Expr{
+						&ast.Text{Value: "a.o", ValuePos: token.Pos(1)},
+						&ast.Text{Value: "b.o", ValuePos: token.Pos(5)},
+					},
+					Pattern: []ast.Expr{&ast.Text{
+						Value:    "%.o",
+						ValuePos: token.Pos(10),
+					}},
+					PatternColon: token.Pos(13),
+					PreReqs: []ast.Expr{&ast.Text{
+						Value:    "%.c",
+						ValuePos: token.Pos(15),
+					}},
+				},
+				"a.o b.o: %.o: %.c\n",
+			),
 			Entry("multiple targets",
 				&ast.Rule{Targets: []ast.Expr{
 					&ast.Text{Value: "target", ValuePos: token.Pos(1)},
@@ -38,7 +87,7 @@ var _ = Describe("Printer", func() {
 					Targets: []ast.Expr{&ast.VarRef{
 						Dollar: token.Pos(1),
 						Open:   token.LPAREN,
-						Name:   "target",
+						Name:   []ast.Expr{&ast.Text{Value: "target", ValuePos: token.Pos(3)}},
 						Close:  token.RPAREN,
 					}},
 				},
@@ -50,12 +99,32 @@ var _ = Describe("Printer", func() {
 					Targets: []ast.Expr{&ast.VarRef{
 						Dollar: token.Pos(1),
 						Open:   token.ILLEGAL,
-						Name:   "t",
+						Name:   []ast.Expr{&ast.Text{Value: "t", ValuePos: token.Pos(2)}},
 						Close:  token.ILLEGAL,
 					}},
 				},
 				"$t:\n",
 			),
+			Entry("computed variable reference target",
+				&ast.Rule{
+					Colon: token.Pos(16),
+					Targets: []ast.Expr{&ast.VarRef{
+						Dollar: token.Pos(1),
+						Open:   token.LPAREN,
+						Name: []ast.Expr{
+							&ast.VarRef{
+								Dollar: token.Pos(3),
+								Open:   token.LBRACE,
+								Name:   []ast.Expr{&ast.Text{Value: "ARCH", ValuePos: token.Pos(5)}},
+								Close:  token.RBRACE,
+							},
+							&ast.Text{Value: "_NAME", ValuePos: token.Pos(10)},
+						},
+						Close: token.RPAREN,
+					}},
+				},
+				"$(${ARCH}_NAME):\n",
+			),
 			Entry("target with prereq",
 				&ast.Rule{
 					Targets: []ast.Expr{&ast.Text{
@@ -95,7 +164,7 @@ var _ = Describe("Printer", func() {
 					PreReqs: []ast.Expr{&ast.VarRef{
 						Dollar: token.Pos(9),
 						Open:   token.LPAREN,
-						Name:   "prereq",
+						Name:   []ast.Expr{&ast.Text{Value: "prereq", ValuePos: token.Pos(11)}},
 						Close:  token.RPAREN,
 					}},
 				},
@@ -112,7 +181,7 @@ var _ = Describe("Printer", func() {
 						Value:    "prereq",
 						ValuePos: token.Pos(9),
 					}},
-					Recipes: []*ast.Recipe{{
+					Recipes: []ast.Obj{&ast.Recipe{
 						Prefix:    token.TAB,
 						PrefixPos: token.Pos(16),
 						Text:      ast.Text{Value: "curl https://example.com"},
@@ -123,13 +192,75 @@ var _ = Describe("Printer", func() {
 			Entry("target with recipe",
 				&ast.Rule{
 					Targets: []ast.Expr{&ast.Text{Value: "target"}},
-					Recipes: []*ast.Recipe{{
+					Recipes: []ast.Obj{&ast.Recipe{
 						Prefix: token.TAB,
 						Text:   ast.Text{Value: "curl https://example.com"},
 					}},
 				},
 				"target:\n\tcurl https://example.com\n",
 			),
+			Entry("target with an inline recipe",
+				&ast.Rule{
+					Targets: []ast.Expr{&ast.Text{Value: "target", ValuePos: token.Pos(1)}},
+					Colon:   token.Pos(7),
+					PreReqs: []ast.Expr{&ast.Text{Value: "prereq", ValuePos: token.Pos(9)}},
+					Recipes: []ast.Obj{
+						&ast.Recipe{
+							Prefix:    token.SEMI,
+							PrefixPos: token.Pos(16),
+							Text:      ast.Text{Value: " recipe", ValuePos: token.Pos(17)},
+						},
+						&ast.Recipe{
+							Prefix:    token.TAB,
+							PrefixPos: token.Pos(25),
+							Text:      ast.Text{Value: "next", ValuePos: token.Pos(26)},
+						},
+					},
+				},
+				"target: prereq ; recipe\n\tnext\n",
+			),
+			Entry("target with recipe modifiers",
+				&ast.Rule{
+					Targets: []ast.Expr{&ast.Text{Value: "target"}},
+					Recipes: []ast.Obj{&ast.Recipe{
+						Prefix:    token.TAB,
+						Modifiers: "@-",
+						Text:      ast.Text{Value: "rm foo"},
+					}},
+				},
+				"target:\n\t@-rm foo\n",
+			),
+			Entry("target with recipe expressions",
+				&ast.Rule{
+					Targets: []ast.Expr{&ast.Text{Value: "target"}},
+					Recipes: []ast.Obj{&ast.Recipe{
+						Prefix: token.TAB,
+						Exprs: []ast.Expr{
+							&ast.Text{Value: "echo "},
+							&ast.VarRef{
+								Open:  token.ILLEGAL,
+								Name:  []ast.Expr{&ast.Text{Value: "@"}},
+								Close: token.ILLEGAL,
+							},
+							&ast.Text{Value: " "},
+							&ast.Escape{},
+							&ast.Text{Value: "HOME"},
+						},
+					}},
+				},
+				"target:\n\techo $@ $$HOME\n",
+			),
+			Entry("target with a custom recipe prefix",
+				&ast.Rule{
+					Targets: []ast.Expr{&ast.Text{Value: "target"}},
+					Recipes: []ast.Obj{&ast.Recipe{
+						Prefix:    token.TEXT,
+						PrefixLit: ">",
+						Text:      ast.Text{Value: "curl https://example.com"},
+					}},
+				},
+				"target:\n>curl https://example.com\n",
+			),
 			func(r *ast.Rule, expected string) {
 				buf := &bytes.Buffer{}
 
@@ -163,7 +294,7 @@ var _ = Describe("Printer", func() {
 			_, err := printer.Fprint(w, &ast.Rule{
 				Targets: []ast.Expr{&ast.Text{Value: "foo"}},
 				PreReqs: []ast.Expr{&ast.Text{Value: "bar"}},
-				Recipes: []*ast.Recipe{{
+				Recipes: []ast.Obj{&ast.Recipe{
 					Prefix: token.TAB,
 					Text:   ast.Text{Value: "baz"},
 				}},
@@ -276,6 +407,48 @@ var _ = Describe("Printer", func() {
 			Expect(buf.String()).To(Equal("FOO :=\n\nBAR :=\n"))
 		})
 
+		It("should write a placeholder for a bad object", func() {
+			buf := &bytes.Buffer{}
+
+			_, err := printer.Fprint(buf, &ast.File{
+				Contents: []ast.Obj{
+					&ast.BadObj{From: token.Pos(1), To: token.Pos(9)},
+					&ast.Variable{
+						Name:  &ast.Text{Value: "d", ValuePos: token.Pos(10)},
+						Op:    token.SIMPLE_ASSIGN,
+						OpPos: token.Pos(12),
+						Value: []ast.Expr{&ast.Text{Value: "e", ValuePos: token.Pos(15)}},
+					},
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("BadObj\nd := e\n"))
+		})
+
+		It("should write tabs indenting lines", func() {
+			buf := &bytes.Buffer{}
+
+			_, err := printer.Fprint(buf, &ast.File{
+				Contents: []ast.Obj{
+					&ast.Variable{
+						Name:  &ast.Text{Value: "FOO", ValuePos: token.Pos(1)},
+						Op:    token.SIMPLE_ASSIGN,
+						OpPos: token.Pos(5),
+					},
+					&ast.Variable{
+						Name:  &ast.Text{Value: "BAR", ValuePos: token.Pos(10)},
+						Op:    token.SIMPLE_ASSIGN,
+						OpPos: token.Pos(14),
+					},
+				},
+				Tabs: []token.Pos{9},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("FOO :=\n\n\tBAR :=\n"))
+		})
+
 		It("should return errors found when writing a Makefile", func() {
 			w := testing.ErrWriter("io error")
 
@@ -313,13 +486,26 @@ var _ = Describe("Printer", func() {
 			Expect(n).To(Equal(7))
 		})
 
+		It("should write a placeholder for a bad expression", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, []ast.Expr{
+				&ast.BadExpr{From: token.Pos(1), To: token.Pos(12)},
+				&ast.Text{Value: "bar", ValuePos: token.Pos(13)},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("BadExpr bar"))
+			Expect(n).To(Equal(11))
+		})
+
 		It("should write apostrophe quoted text", func() {
 			buf := &bytes.Buffer{}
 
 			n, err := printer.Fprint(buf, &ast.QuotedExpr{
 				Quote: token.APOS,
 				Open:  token.Pos(1),
-				Value: &ast.Text{Value: "foo", ValuePos: token.Pos(2)},
+				Value: []ast.Expr{&ast.Text{Value: "foo", ValuePos: token.Pos(2)}},
 				Close: token.Pos(5),
 			})
 
@@ -334,7 +520,7 @@ var _ = Describe("Printer", func() {
 			n, err := printer.Fprint(buf, &ast.QuotedExpr{
 				Quote: token.QUOTE,
 				Open:  token.Pos(1),
-				Value: &ast.Text{Value: "bar", ValuePos: token.Pos(2)},
+				Value: []ast.Expr{&ast.Text{Value: "bar", ValuePos: token.Pos(2)}},
 				Close: token.Pos(5),
 			})
 
@@ -342,6 +528,140 @@ var _ = Describe("Printer", func() {
 			Expect(buf.String()).To(Equal(`"bar"`))
 			Expect(n).To(Equal(5))
 		})
+
+		It("should write a substitution reference", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.SubstRef{
+				Dollar:   token.Pos(1),
+				Open:     token.LPAREN,
+				Name:     []ast.Expr{&ast.Text{Value: "SRCS", ValuePos: token.Pos(3)}},
+				Colon:    token.Pos(7),
+				From:     []ast.Expr{&ast.Text{Value: "%.c", ValuePos: token.Pos(8)}},
+				Assign:   token.Pos(11),
+				To:       []ast.Expr{&ast.Text{Value: "%.o", ValuePos: token.Pos(12)}},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(15),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("$(SRCS:%.c=%.o)"))
+			Expect(n).To(Equal(15))
+		})
+
+		It("should write a substitution reference with an empty suffix", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.SubstRef{
+				Dollar:   token.Pos(1),
+				Open:     token.LBRACE,
+				Name:     []ast.Expr{&ast.Text{Value: "OBJS", ValuePos: token.Pos(3)}},
+				Colon:    token.Pos(7),
+				Assign:   token.Pos(8),
+				To:       []ast.Expr{&ast.Text{Value: ".o", ValuePos: token.Pos(9)}},
+				Close:    token.RBRACE,
+				ClosePos: token.Pos(11),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("${OBJS:=.o}"))
+			Expect(n).To(Equal(11))
+		})
+
+		It("should write a function call", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.FuncCall{
+				Dollar:  token.Pos(1),
+				Open:    token.LPAREN,
+				Name:    token.SUBST,
+				NamePos: token.Pos(3),
+				Args: [][]ast.Expr{
+					{&ast.Text{Value: ":", ValuePos: token.Pos(9)}},
+					nil,
+					{&ast.VarRef{
+						Dollar: token.Pos(13),
+						Open:   token.LPAREN,
+						Name:   []ast.Expr{&ast.Text{Value: "PATH", ValuePos: token.Pos(15)}},
+						Close:  token.RPAREN,
+					}},
+				},
+				Commas:   []token.Pos{10, 12},
+				Close:    token.RPAREN,
+				ClosePos: token.Pos(20),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("$(subst :, ,$(PATH))"))
+			Expect(n).To(Equal(20))
+		})
+
+		It("should write a function call with braces", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.FuncCall{
+				Dollar:   token.Pos(1),
+				Open:     token.LBRACE,
+				Name:     token.WILDCARD,
+				NamePos:  token.Pos(3),
+				Args:     [][]ast.Expr{{&ast.Text{Value: "*.go", ValuePos: token.Pos(12)}}},
+				Close:    token.RBRACE,
+				ClosePos: token.Pos(16),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("${wildcard *.go}"))
+			Expect(n).To(Equal(16))
+		})
+
+		It("should print an ifeq directive with an empty argument", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.IfeqDir{
+				Tok:    token.IFEQ,
+				TokPos: token.Pos(1),
+				Open:   token.Pos(6),
+				Arg1: []ast.Expr{&ast.VarRef{
+					Dollar: token.Pos(7),
+					Open:   token.LPAREN,
+					Name:   []ast.Expr{&ast.Text{Value: "CI", ValuePos: token.Pos(9)}},
+					Close:  token.RPAREN,
+				}},
+				Comma: token.Pos(12),
+				Close: token.Pos(13),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("ifeq ($(CI),)"))
+			Expect(n).To(Equal(13))
+		})
+
+		It("should print an ifeq directive with empty quotes", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.IfeqDir{
+				Tok:    token.IFEQ,
+				TokPos: token.Pos(1),
+				Arg1: []ast.Expr{&ast.QuotedExpr{
+					Quote: token.QUOTE,
+					Open:  token.Pos(6),
+					Value: []ast.Expr{
+						&ast.Text{Value: "a", ValuePos: token.Pos(7)},
+						&ast.Text{Value: "b", ValuePos: token.Pos(9)},
+					},
+					Close: token.Pos(11),
+				}},
+				Arg2: []ast.Expr{&ast.QuotedExpr{
+					Quote: token.QUOTE,
+					Open:  token.Pos(13),
+					Close: token.Pos(14),
+				}},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal(`ifeq "a b " ""`))
+			Expect(n).To(Equal(14))
+		})
 	})
 
 	Describe("variables", func() {
@@ -412,6 +732,25 @@ var _ = Describe("Printer", func() {
 				Expect(n).To(Equal(14))
 			})
 
+			It("should write a continued variable", func() {
+				buf := &bytes.Buffer{}
+
+				n, err := printer.Fprint(buf, &ast.Variable{
+					Name:  &ast.Text{Value: "TEST", ValuePos: token.Pos(1)},
+					Op:    token.SIMPLE_ASSIGN,
+					OpPos: token.Pos(6),
+					Value: []ast.Expr{
+						&ast.Text{Value: "a", ValuePos: token.Pos(9)},
+						&ast.Continuation{Backslash: token.Pos(11), Text: "\\\n\t"},
+						&ast.Text{Value: "b", ValuePos: token.Pos(14)},
+					},
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buf.String()).To(Equal("TEST := a \\\n\tb\n"))
+				Expect(n).To(Equal(15))
+			})
+
 			It("should return write errors", func() {
 				w := testing.NewErrAfterWriter(1)
 
@@ -430,6 +769,70 @@ var _ = Describe("Printer", func() {
 		})
 	})
 
+	Describe("target variables", func() {
+		It("should write a target-specific variable", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.TargetVariable{
+				Targets: []ast.Expr{&ast.Text{Value: "debug", ValuePos: token.Pos(1)}},
+				Colon:   token.Pos(6),
+				Variable: &ast.Variable{
+					Name:  &ast.Text{Value: "CFLAGS", ValuePos: token.Pos(8)},
+					Op:    token.APPEND_ASSIGN,
+					OpPos: token.Pos(15),
+					Value: []ast.Expr{&ast.Text{Value: "-g", ValuePos: token.Pos(18)}},
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("debug: CFLAGS += -g\n"))
+			Expect(n).To(Equal(20))
+		})
+
+		It("should write a double-colon target-specific variable", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.TargetVariable{
+				Targets: []ast.Expr{&ast.Text{Value: "a", ValuePos: token.Pos(1)}},
+				Tok:     token.DOUBLE_COLON,
+				Colon:   token.Pos(2),
+				Variable: &ast.Variable{
+					Name:  &ast.Text{Value: "X", ValuePos: token.Pos(5)},
+					Op:    token.RECURSIVE_ASSIGN,
+					OpPos: token.Pos(7),
+					Value: []ast.Expr{&ast.Text{Value: "1", ValuePos: token.Pos(9)}},
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("a:: X = 1\n"))
+			Expect(n).To(Equal(10))
+		})
+
+		It("should write a pattern-specific variable with modifiers", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.TargetVariable{
+				Targets: []ast.Expr{&ast.Text{Value: "%.o", ValuePos: token.Pos(1)}},
+				Colon:   token.Pos(4),
+				Variable: &ast.Variable{
+					Modifiers: []*ast.Modifier{
+						{Tok: token.OVERRIDE, TokPos: token.Pos(6)},
+						{Tok: token.PRIVATE, TokPos: token.Pos(15)},
+					},
+					Name:  &ast.Text{Value: "X", ValuePos: token.Pos(23)},
+					Op:    token.SIMPLE_ASSIGN,
+					OpPos: token.Pos(25),
+					Value: []ast.Expr{&ast.Text{Value: "1", ValuePos: token.Pos(28)}},
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("%.o: override private X := 1\n"))
+			Expect(n).To(Equal(29))
+		})
+	})
+
 	Describe("directives", func() {
 		It("should print an ifeq directive", func() {
 			buf := &bytes.Buffer{}
@@ -438,15 +841,15 @@ var _ = Describe("Printer", func() {
 				Tok:    token.IFEQ,
 				TokPos: token.Pos(1),
 				Open:   token.Pos(6),
-				Arg1: &ast.Text{
+				Arg1: []ast.Expr{&ast.Text{
 					Value:    "foo",
 					ValuePos: token.Pos(7),
-				},
+				}},
 				Comma: token.Pos(10),
-				Arg2: &ast.Text{
+				Arg2: []ast.Expr{&ast.Text{
 					Value:    "bar",
 					ValuePos: token.Pos(12),
-				},
+				}},
 				Close: token.Pos(15),
 			})
 
@@ -461,24 +864,24 @@ var _ = Describe("Printer", func() {
 			n, err := printer.Fprint(buf, &ast.IfeqDir{
 				Tok:    token.IFEQ,
 				TokPos: token.Pos(1),
-				Arg1: &ast.QuotedExpr{
+				Arg1: []ast.Expr{&ast.QuotedExpr{
 					Quote: token.APOS,
 					Open:  token.Pos(6),
-					Value: &ast.Text{
+					Value: []ast.Expr{&ast.Text{
 						Value:    "foo",
 						ValuePos: token.Pos(7),
-					},
+					}},
 					Close: token.Pos(10),
-				},
-				Arg2: &ast.QuotedExpr{
+				}},
+				Arg2: []ast.Expr{&ast.QuotedExpr{
 					Quote: token.QUOTE,
 					Open:  token.Pos(12),
-					Value: &ast.Text{
+					Value: []ast.Expr{&ast.Text{
 						Value:    "bar",
 						ValuePos: token.Pos(13),
-					},
+					}},
 					Close: token.Pos(16),
-				},
+				}},
 			})
 
 			Expect(err).NotTo(HaveOccurred())
@@ -503,6 +906,27 @@ var _ = Describe("Printer", func() {
 			Expect(n).To(Equal(9))
 		})
 
+		It("should print an ifdef directive with a trailing comment", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.IfdefDir{
+				Tok:    token.IFDEF,
+				TokPos: token.Pos(1),
+				VarName: &ast.Text{
+					Value:    "foo",
+					ValuePos: token.Pos(7),
+				},
+				Comment: &ast.Comment{
+					Pound: token.Pos(11),
+					Text:  "bar",
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("ifdef foo # bar"))
+			Expect(n).To(Equal(15))
+		})
+
 		It("should print an if block", func() {
 			buf := &bytes.Buffer{}
 
@@ -530,6 +954,44 @@ var _ = Describe("Printer", func() {
 			Expect(n).To(Equal(21))
 		})
 
+		It("should print a conditional directive in recipes", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.Rule{
+				Targets: []ast.Expr{&ast.Text{Value: "build", ValuePos: token.Pos(1)}},
+				Colon:   token.Pos(6),
+				Recipes: []ast.Obj{
+					&ast.Recipe{
+						Prefix:    token.TAB,
+						PrefixPos: token.Pos(8),
+						Text:      ast.Text{Value: "echo a", ValuePos: token.Pos(9)},
+					},
+					&ast.IfBlock{
+						Directive: &ast.IfdefDir{
+							Tok:     token.IFDEF,
+							TokPos:  token.Pos(16),
+							VarName: &ast.Text{Value: "DEBUG", ValuePos: token.Pos(22)},
+						},
+						Text: []ast.Obj{&ast.Recipe{
+							Prefix:    token.TAB,
+							PrefixPos: token.Pos(28),
+							Text:      ast.Text{Value: "echo b", ValuePos: token.Pos(29)},
+						}},
+						Endif: token.Pos(36),
+					},
+					&ast.Recipe{
+						Prefix:    token.TAB,
+						PrefixPos: token.Pos(42),
+						Text:      ast.Text{Value: "echo c", ValuePos: token.Pos(43)},
+					},
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("build:\n\techo a\nifdef DEBUG\n\techo b\nendif\n\techo c\n"))
+			Expect(n).To(Equal(49))
+		})
+
 		It("should print an if block with an else", func() {
 			buf := &bytes.Buffer{}
 
@@ -611,6 +1073,135 @@ var _ = Describe("Printer", func() {
 			Expect(buf.String()).To(Equal("ifdef foo\nbar:\nelse ifdef baz\nbin:\nendif\n"))
 			Expect(n).To(Equal(41))
 		})
+
+		It("should print a define directive", func() {
+			buf := &bytes.Buffer{}
+
+			n, err := printer.Fprint(buf, &ast.DefineDir{
+				Define: token.Pos(1),
+				Name: &ast.Text{
+					Value:    "foo",
+					ValuePos: token.Pos(8),
+				},
+				Op:    token.RECURSIVE_ASSIGN,
+				OpPos: token.Pos(12),
+				Body: []*ast.Text{
+					{Value: "bar", ValuePos: token.Pos(14)},
+					{Value: "\tbaz", ValuePos: token.Pos(18)},
+				},
+				Endef: token.Pos(23),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("define foo =\nbar\n\tbaz\nendef\n"))
+			Expect(n).To(Equal(28))
+		})
+
+		DescribeTable("should print an include directive",
+			Entry(nil, token.INCLUDE, "include foo.mk $(BAR)\n"),
+			Entry(nil, token.DASH_INCLUDE, "-include foo.mk $(BAR)\n"),
+			Entry(nil, token.SINCLUDE, "sinclude foo.mk $(BAR)\n"),
+			func(tok token.Token, expected string) {
+				buf := &bytes.Buffer{}
+				start := len(tok.String()) + 2
+
+				n, err := printer.Fprint(buf, &ast.IncludeDir{
+					Tok:    tok,
+					TokPos: token.Pos(1),
+					Files: []ast.Expr{
+						&ast.Text{Value: "foo.mk", ValuePos: token.Pos(start)},
+						&ast.VarRef{
+							Dollar: token.Pos(start + 7),
+							Open:   token.LPAREN,
+							Name:   []ast.Expr{&ast.Text{Value: "BAR", ValuePos: token.Pos(start + 9)}},
+							Close:  token.RPAREN,
+						},
+					},
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buf.String()).To(Equal(expected))
+				Expect(n).To(Equal(len(expected)))
+			},
+		)
+	})
+
+	It("should print an export directive", func() {
+		buf := &bytes.Buffer{}
+
+		n, err := printer.Fprint(buf, &ast.ExportDir{
+			Export: token.Pos(1),
+			Names: []ast.Expr{
+				&ast.Text{Value: "FOO", ValuePos: token.Pos(8)},
+				&ast.Text{Value: "BAR", ValuePos: token.Pos(12)},
+			},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("export FOO BAR\n"))
+		Expect(n).To(Equal(15))
+	})
+
+	It("should print an unexport directive", func() {
+		buf := &bytes.Buffer{}
+
+		n, err := printer.Fprint(buf, &ast.UnexportDir{Unexport: token.Pos(1)})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("unexport\n"))
+		Expect(n).To(Equal(9))
+	})
+
+	It("should print an undefine directive with a modifier", func() {
+		buf := &bytes.Buffer{}
+
+		n, err := printer.Fprint(buf, &ast.UndefineDir{
+			Modifiers: []*ast.Modifier{{Tok: token.OVERRIDE, TokPos: token.Pos(1)}},
+			Undefine:  token.Pos(10),
+			Name:      &ast.Text{Value: "FOO", ValuePos: token.Pos(19)},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("override undefine FOO\n"))
+		Expect(n).To(Equal(22))
+	})
+
+	It("should print a variable with modifiers", func() {
+		buf := &bytes.Buffer{}
+
+		n, err := printer.Fprint(buf, &ast.Variable{
+			Modifiers: []*ast.Modifier{
+				{Tok: token.OVERRIDE, TokPos: token.Pos(1)},
+				{Tok: token.EXPORT, TokPos: token.Pos(10)},
+			},
+			Name:  &ast.Text{Value: "FOO", ValuePos: token.Pos(17)},
+			Op:    token.SIMPLE_ASSIGN,
+			OpPos: token.Pos(21),
+			Value: []ast.Expr{&ast.Text{Value: "bar", ValuePos: token.Pos(24)}},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("override export FOO := bar\n"))
+		Expect(n).To(Equal(27))
+	})
+
+	It("should print a vpath directive", func() {
+		buf := &bytes.Buffer{}
+
+		n, err := printer.Fprint(buf, &ast.VpathDir{
+			Vpath:   token.Pos(1),
+			Pattern: &ast.Text{Value: "%.c", ValuePos: token.Pos(7)},
+			Directories: []ast.Expr{
+				&ast.Text{Value: "src", ValuePos: token.Pos(11)},
+				&ast.Text{Value: "lib", ValuePos: token.Pos(15)},
+				&ast.Text{Value: "../foo", ValuePos: token.Pos(19)},
+			},
+			Colons: []token.Pos{token.Pos(14)},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("vpath %.c src:lib ../foo\n"))
+		Expect(n).To(Equal(25))
 	})
 
 	When("a token.File is provided", func() {
@@ -14,12 +14,39 @@ func ScanTokens(data []byte, atEOF bool) (advance int, token []byte, err error)
 	switch data[0] {
 	case ' ':
 		return 1, data[:1], nil
-	case '?':
+	case '?', '+', '!':
+		if len(data) < 2 && !atEOF {
+			return 0, nil, nil
+		}
 		if len(data) > 1 && data[1] == '=' {
 			return 2, data[:2], nil
 		}
+	case '&':
+		if len(data) < 2 && !atEOF {
+			return 0, nil, nil
+		}
+		if len(data) > 1 && data[1] == ':' {
+			return 2, data[:2], nil
+		}
+	case '\\':
+		if len(data) < 2 && !atEOF {
+			return 0, nil, nil
+		}
+		if len(data) > 1 && data[1] == '\n' {
+			// A continuation carries the indentation of the line it continues onto
+			i := bytes.IndexFunc(data[2:], func(r rune) bool {
+				return r != ' ' && r != '\t'
+			})
+			if i < 0 && !atEOF {
+				return 0, nil, nil
+			}
+			if i < 0 {
+				return len(data), data, nil
+			}
+			return i + 2, data[:i+2], nil
+		}
 	case ':':
-		if len(data) == 1 && !atEOF {
+		if len(data) < 4 && !atEOF {
 			return 0, nil, nil // We need more info to make a decision
 		}
 		if bytes.HasPrefix(data, []byte(":::=")) {
@@ -31,15 +58,18 @@ func ScanTokens(data []byte, atEOF bool) (advance int, token []byte, err error)
 		if bytes.HasPrefix(data, []byte(":=")) {
 			return 2, data[:2], nil
 		}
+		if bytes.HasPrefix(data, []byte("::")) {
+			return 2, data[:2], nil
+		}
 
 		fallthrough
 	case '#':
 		fallthrough
-	case '\n', '\t', '$', '(', ')', '{', '}', ',', '\'', '"':
+	case '\n', '\t', '$', '(', ')', '{', '}', ',', ';', '\'', '"':
 		return 1, data[:1], nil
 	}
 
-	if i := bytes.IndexAny(data, ":\n\t (){},'\""); i > 0 {
+	if i := indexDelim(data); i > 0 {
 		return i, data[:i], nil
 	}
 
@@ -49,3 +79,29 @@ func ScanTokens(data []byte, atEOF bool) (advance int, token []byte, err error)
 		return 0, nil, nil
 	}
 }
+
+// indexDelim returns the index of the first token delimiter in data,
+// or -1 if there is none. A backslash only delimits a token when it
+// is followed by a newline, and '?', '+', and '!' only when followed by '='.
+func indexDelim(data []byte) int {
+	for i, b := range data {
+		switch b {
+		case ':', '$', '\n', '\t', ' ', '(', ')', '{', '}', ',', ';', '\'', '"':
+			return i
+		case '\\':
+			if i+1 < len(data) && data[i+1] == '\n' {
+				return i
+			}
+		case '&':
+			if i+1 < len(data) && data[i+1] == ':' {
+				return i
+			}
+		case '?', '+', '!':
+			if i+1 < len(data) && data[i+1] == '=' {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
@@ -25,6 +25,21 @@ var _ = Describe("Scan", func() {
 			Entry("multiple targets with a separating space",
 				"target target2 :", []string{"target", " ", "target2", " ", ":"},
 			),
+			Entry("double-colon target",
+				"target::", []string{"target", "::"},
+			),
+			Entry("double-colon target with a prereq",
+				"target:: prereq", []string{"target", "::", " ", "prereq"},
+			),
+			Entry("grouped targets",
+				"foo.h foo.c &: foo.y", []string{"foo.h", " ", "foo.c", " ", "&:", " ", "foo.y"},
+			),
+			Entry("grouped targets without a separating space",
+				"foo.h foo.c&: foo.y", []string{"foo.h", " ", "foo.c", "&:", " ", "foo.y"},
+			),
+			Entry("target containing an ampersand",
+				"a&b:", []string{"a&b", ":"},
+			),
 			Entry("target with a trailing newline",
 				"target:\n", []string{"target", ":", "\n"},
 			),
@@ -47,6 +62,9 @@ var _ = Describe("Scan", func() {
 				"target:\n\trecipe\n\trecipe2",
 				[]string{"target", ":", "\n", "\t", "recipe", "\n", "\t", "recipe2"},
 			),
+			Entry("target with an inline recipe",
+				"target: prereq;recipe", []string{"target", ":", " ", "prereq", ";", "recipe"},
+			),
 			Entry("comment",
 				"# comment", []string{"#", " ", "comment"},
 			),
@@ -86,12 +104,49 @@ var _ = Describe("Scan", func() {
 			Entry("shell variable",
 				"VAR != test", []string{"VAR", " ", "!=", " ", "test"},
 			),
+			Entry("append variable",
+				"VAR += test", []string{"VAR", " ", "+=", " ", "test"},
+			),
+			Entry("append variable without separating spaces",
+				"VAR+=test", []string{"VAR", "+=", "test"},
+			),
+			Entry("ifndef variable without separating spaces",
+				"VAR?=test", []string{"VAR", "?=", "test"},
+			),
+			Entry("shell variable without separating spaces",
+				"VAR!=test", []string{"VAR", "!=", "test"},
+			),
+			Entry("text containing a plus",
+				"a+b", []string{"a+b"},
+			),
 			Entry("info function",
 				"$(info thing)", []string{"$", "(", "info", " ", "thing", ")"},
 			),
 			Entry("subst function",
 				"$(subst from,to,text)", []string{"$", "(", "subst", " ", "from", ",", "to", ",", "text", ")"},
 			),
+			Entry("nested function",
+				"$(patsubst %.c,%.o,$(SRCS))",
+				[]string{"$", "(", "patsubst", " ", "%.c", ",", "%.o", ",", "$", "(", "SRCS", ")", ")"},
+			),
+			Entry("variable reference inside a word",
+				"obj/$(NAME).o", []string{"obj/", "$", "(", "NAME", ")", ".o"},
+			),
+			Entry("automatic variable inside a word",
+				"a$@", []string{"a", "$", "@"},
+			),
+			Entry("continued line",
+				"foo \\\nbar", []string{"foo", " ", "\\\n", "bar"},
+			),
+			Entry("continued line without a separating space",
+				"foo\\\nbar", []string{"foo", "\\\n", "bar"},
+			),
+			Entry("continued line with indentation",
+				"foo \\\n\t  bar", []string{"foo", " ", "\\\n\t  ", "bar"},
+			),
+			Entry("escaped character",
+				"foo\\ bar", []string{"foo\\", " ", "bar"},
+			),
 			Entry("ifeq directive",
 				"ifeq (foo, bar)", []string{"ifeq", " ", "(", "foo", ",", " ", "bar", ")"},
 			),
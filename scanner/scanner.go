@@ -42,23 +42,23 @@ func New(r io.Reader, file *token.File) *Scanner {
 }
 
 func (s *Scanner) next() {
-	s.offset = s.rdOffset
-	if bytes.ContainsRune(s.s.Bytes(), '\n') {
-		s.file.AddLine(s.offset)
+	if i := bytes.LastIndexByte(s.s.Bytes(), '\n'); i >= 0 {
+		s.file.AddLine(s.offset + i + 1)
 	}
+	s.offset = s.rdOffset
 	s.done = !s.s.Scan()
 	s.rdOffset += len(s.s.Bytes())
 }
 
 func (s *Scanner) skipWhitespace() {
-	for bytes.ContainsAny(s.s.Bytes(), " \r") {
+	for bytes.ContainsAny(s.s.Bytes(), " \r") && !isContinuation(s.s.Bytes()) {
 		s.next()
 	}
 }
 
 func (s *Scanner) scanComment() string {
 	b := strings.Builder{}
-	for !s.done && !bytes.ContainsRune(s.s.Bytes(), '\n') {
+	for !s.done && (isContinuation(s.s.Bytes()) || !bytes.ContainsRune(s.s.Bytes(), '\n')) {
 		b.Write(s.s.Bytes())
 		s.next()
 	}
@@ -88,6 +88,10 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 	var atNewline bool
 
 	switch txt := s.s.Text(); {
+	case isContinuation(s.s.Bytes()):
+		lit = txt
+		tok = token.CONTINUATION
+		s.next()
 	case token.IsLit(txt):
 		lit = txt
 		s.next()
@@ -111,6 +115,8 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 			tok = token.IFNDEF_ASSIGN
 		case "!=":
 			tok = token.SHELL_ASSIGN
+		case "+=":
+			tok = token.APPEND_ASSIGN
 		case ",":
 			tok = token.COMMA
 		case "'":
@@ -134,6 +140,10 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 			tok = token.DOLLAR
 		case ":":
 			tok = token.COLON
+		case "::":
+			tok = token.DOUBLE_COLON
+		case "&:":
+			tok = token.AND_COLON
 		case ";":
 			tok = token.SEMI
 		case "|":
@@ -153,3 +163,8 @@ func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
 
 	return
 }
+
+// isContinuation reports whether b is a backslash-newline token.
+func isContinuation(b []byte) bool {
+	return bytes.HasPrefix(b, []byte("\\\n"))
+}
@@ -112,6 +112,7 @@ var _ = Describe("Scanner", func() {
 		Entry(nil, "ident :::="),
 		Entry(nil, "ident ?="),
 		Entry(nil, "ident !="),
+		Entry(nil, "ident +="),
 		Entry(nil, "ident ("),
 		Entry(nil, "ident )"),
 		Entry(nil, "ident {"),
@@ -136,6 +137,8 @@ var _ = Describe("Scanner", func() {
 	DescribeTable("Scan non-ident tokens",
 		Entry(nil, "$", token.DOLLAR),
 		Entry(nil, ":", token.COLON),
+		Entry(nil, "::", token.DOUBLE_COLON),
+		Entry(nil, "&:", token.AND_COLON),
 		Entry(nil, ";", token.SEMI),
 		Entry(nil, "|", token.PIPE),
 		Entry(nil, "=", token.RECURSIVE_ASSIGN),
@@ -144,6 +147,7 @@ var _ = Describe("Scanner", func() {
 		Entry(nil, ":::=", token.IMMEDIATE_ASSIGN),
 		Entry(nil, "?=", token.IFNDEF_ASSIGN),
 		Entry(nil, "!=", token.SHELL_ASSIGN),
+		Entry(nil, "+=", token.APPEND_ASSIGN),
 		Entry(nil, "(", token.LPAREN),
 		Entry(nil, ")", token.RPAREN),
 		Entry(nil, "{", token.LBRACE),
@@ -199,6 +203,7 @@ var _ = Describe("Scanner", func() {
 		Entry(nil, "\n="),
 		Entry(nil, "\n?="),
 		Entry(nil, "\n!="),
+		Entry(nil, "\n+="),
 		Entry(nil, "\n|"),
 		Entry(nil, "\n\t"),
 		Entry(nil, "\n{"),
@@ -241,6 +246,7 @@ var _ = Describe("Scanner", func() {
 		Entry(nil, ":::= foo", 6),
 		Entry(nil, "?= foo", 4),
 		Entry(nil, "!= foo", 4),
+		Entry(nil, "+= foo", 4),
 		Entry(nil, "( foo", 3),
 		Entry(nil, ") foo", 3),
 		Entry(nil, "{ foo", 3),
@@ -260,6 +266,7 @@ var _ = Describe("Scanner", func() {
 		Entry(nil, ":::= foo bar", 6),
 		Entry(nil, "?= foo bar", 4),
 		Entry(nil, "!= foo bar", 4),
+		Entry(nil, "+= foo bar", 4),
 		Entry(nil, "( foo bar", 3),
 		Entry(nil, ") foo bar", 3),
 		Entry(nil, "{ foo bar", 3),
@@ -296,6 +303,7 @@ var _ = Describe("Scanner", func() {
 		Entry(nil, ":::=\nfoo", 5),
 		Entry(nil, "?=\nfoo", 3),
 		Entry(nil, "!=\nfoo", 3),
+		Entry(nil, "+=\nfoo", 3),
 		Entry(nil, "(\nfoo", 2),
 		Entry(nil, ")\nfoo", 2),
 		Entry(nil, "{\nfoo", 2),
@@ -333,8 +341,57 @@ var _ = Describe("Scanner", func() {
 		},
 	)
 
+	DescribeTable("continued lines",
+		Entry(nil, "foo \\\nbar", 5, "\\\n"),
+		Entry(nil, "foo\\\nbar", 4, "\\\n"),
+		Entry(nil, "foo \\\n  bar", 5, "\\\n  "),
+		Entry(nil, "foo \\\n\tbar", 5, "\\\n\t"),
+		func(input string, contPos int, expected string) {
+			buf := bytes.NewBufferString(input)
+			s := scanner.New(buf, file)
+
+			_, _, _ = s.Scan()
+			pos, tok, lit := s.Scan()
+			Expect(tok).To(Equal(token.CONTINUATION))
+			Expect(pos).To(Equal(token.Pos(contPos)))
+			Expect(lit).To(Equal(expected))
+
+			pos, tok, lit = s.Scan()
+			Expect(tok).To(Equal(token.TEXT))
+			Expect(lit).To(Equal("bar"))
+			Expect(s.Position(pos)).To(Equal(token.Position{
+				Filename: file.Name(),
+				Offset:   contPos + len(expected) - file.Base(),
+				Line:     2,
+				Column:   len(expected) - 1,
+			}))
+		},
+	)
+
+	It("should continue comments onto the next line", func() {
+		buf := bytes.NewBufferString("# foo \\\n bar\nbaz")
+		s := scanner.New(buf, file)
+
+		_, tok, lit := s.Scan()
+		Expect(tok).To(Equal(token.COMMENT))
+		Expect(lit).To(Equal(" foo \\\n bar"))
+
+		_, tok, _ = s.Scan()
+		Expect(tok).To(Equal(token.NEWLINE))
+
+		pos, tok, lit := s.Scan()
+		Expect(tok).To(Equal(token.TEXT))
+		Expect(lit).To(Equal("baz"))
+		Expect(s.Position(pos).Line).To(Equal(3))
+	})
+
 	DescribeTable("directives",
 		Entry(nil, "ifeq"),
+		Entry(nil, "define"),
+		Entry(nil, "endef"),
+		Entry(nil, "include"),
+		Entry(nil, "-include"),
+		Entry(nil, "sinclude"),
 		func(input string) {
 			buf := bytes.NewBufferString(input)
 			s := scanner.New(buf, file)
@@ -0,0 +1,4 @@
+CFLAGS+= -Wall
+LDFLAGS?=-lm
+VERSION!=git describe
+debug: CFLAGS+=-g
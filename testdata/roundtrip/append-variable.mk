@@ -0,0 +1,3 @@
+CFLAGS := -O2
+CFLAGS += -Wall -Werror
+LDFLAGS +=
@@ -0,0 +1,4 @@
+CFLAGS := $($(ARCH)_CFLAGS) ${${OS}_${ARCH}_FLAGS}
+
+$(BIN_$(OS))/app: $(SRCS)
+	$(CC) $($(ARCH)_CFLAGS) -o $@
@@ -0,0 +1,14 @@
+SRCS := foo.c \
+	bar.c   \
+  baz.c
+
+OBJS := $(patsubst %.c,%.o,\
+	$(SRCS))
+
+all: foo.o \
+     bar.o
+	echo one \
+	  two
+
+# a comment \
+  continued
@@ -0,0 +1,3 @@
+define FOO :=
+bar
+endef   # trailing comment
@@ -0,0 +1,2 @@
+define EMPTY :=
+endef
@@ -0,0 +1,6 @@
+export define FOO
+bar
+endef
+override define BAR :=
+	baz
+endef
@@ -0,0 +1,6 @@
+define OUTER
+  define INNER
+	indented  text
+
+  endef
+endef
@@ -0,0 +1,4 @@
+define TWO_LINES
+echo foo
+echo $(BAR)
+endef
@@ -0,0 +1,7 @@
+clean::
+	rm -f *.o
+
+clean:: distclean
+	rm -rf bin
+
+install :: $(BIN)
@@ -0,0 +1,4 @@
+export FOO BAR # comment
+unexport   # comment
+export
+override undefine FOO  # comment
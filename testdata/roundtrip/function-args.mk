@@ -0,0 +1 @@
+X := $(if $(A),$(call f, x ,y),)
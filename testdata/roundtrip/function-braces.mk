@@ -0,0 +1 @@
+FILES := ${wildcard src/*.go}
@@ -0,0 +1 @@
+HASH := $(shell echo a # b) c # d
@@ -0,0 +1 @@
+PATHS := $(subst :, ,$(PATH))
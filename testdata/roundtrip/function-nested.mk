@@ -0,0 +1,2 @@
+SRCS := main.c util.c
+OBJS := $(patsubst %.c,%.o,$(SRCS))
@@ -0,0 +1,2 @@
+build: $(addprefix obj/,$(OBJS))
+	$(CC) -o $@ $^
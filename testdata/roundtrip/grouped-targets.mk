@@ -0,0 +1,5 @@
+foo.h foo.c &: foo.y
+	bison --defines=foo.h -o foo.c $<
+
+gen.go gen_test.go&: gen.yaml | bin/gen
+	bin/gen $<
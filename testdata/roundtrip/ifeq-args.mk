@@ -0,0 +1,9 @@
+ifeq ($(CI),)
+FLAGS := --local
+endif
+ifneq ($(strip $(filter a,$(LIST))),)
+target:
+endif
+ifeq "$(A) $(B)" ""
+EMPTY := true
+endif
@@ -0,0 +1,2 @@
+include foo.mk # trailing comment
+-include bar.mk   # another comment
@@ -0,0 +1,3 @@
+include mk/tools.mk
+
+-include .env
@@ -0,0 +1 @@
+include foo.mk bar.mk
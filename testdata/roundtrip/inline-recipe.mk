@@ -0,0 +1,6 @@
+target: prereq ; echo $@
+	echo next
+
+target2:;echo $@
+
+target3: prereq | order-only; echo $^
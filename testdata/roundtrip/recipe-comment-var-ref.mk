@@ -1,2 +1,3 @@
 target:
 	@echo $@: # keep this comment
+	echo $(CC) # uses $(CFLAGS)
@@ -0,0 +1,7 @@
+build:
+	echo a
+# explain the next step
+	echo b
+# trailing comment
+
+clean:
@@ -0,0 +1,21 @@
+build:
+	go build ./...
+ifdef DEBUG
+	@echo debug build
+else ifeq ($(OS),Windows_NT)
+	@echo windows build
+else
+	@echo release build
+endif
+	@touch $@
+
+test: build
+ifndef CI
+	go test ./...
+
+	@echo done
+endif
+
+ifdef VERBOSE
+FLAGS := -v
+endif
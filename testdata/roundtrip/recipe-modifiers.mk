@@ -0,0 +1,8 @@
+clean:
+	@echo cleaning
+	-rm -f *.o
+	+$(MAKE) -C sub clean
+	@-rm -rf bin
+	@ - rm -rf dist
+	  @echo indented
+all: ; @echo inline
@@ -0,0 +1,13 @@
+.RECIPEPREFIX = |
+target:
+|recipe text
+| echo $@
+
+.RECIPEPREFIX := >
+target2: prereq
+>recipe text
+	VAR := value
+
+.RECIPEPREFIX :=
+target3:
+	recipe text
@@ -0,0 +1,7 @@
+CC := gcc
+
+app: main.o util.o
+	$(CC) -o $@ $^ $(LDFLAGS)
+	@echo built ${@F} from $(words $^) objects
+	for f in $^; do echo $$f; done
+	-$(RM) $(patsubst %.o,%.d,$^)
@@ -0,0 +1 @@
+sinclude mk/*.mk
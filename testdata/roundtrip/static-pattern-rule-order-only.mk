@@ -0,0 +1,2 @@
+foo.o bar.o : %.o : %.c | obj
+	$(CC) -c $< -o obj/$@
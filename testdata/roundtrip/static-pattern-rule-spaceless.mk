@@ -0,0 +1 @@
+$(TARGETS):%:%.in
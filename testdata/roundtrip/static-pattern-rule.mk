@@ -0,0 +1,4 @@
+OBJS := foo.o bar.o
+
+$(OBJS): %.o: %.c
+	$(CC) -c $(CFLAGS) $< -o $@
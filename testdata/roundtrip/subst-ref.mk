@@ -0,0 +1,7 @@
+SRCS := main.c util.c
+OBJS := $(SRCS:.c=.o)
+DEPS := ${OBJS:%.o=%.d}
+LIBS := $(NAMES:=.a) $(SRCS:%=$(DIR)/%)
+
+$(BIN): $(SRCS:.c=.o)
+	$(CC) -o $@ $^
@@ -0,0 +1,10 @@
+debug: CFLAGS += -g
+
+%.o: private EXTRA := 1
+
+release test: override export GOFLAGS = -trimpath $(FLAGS)
+
+debug:
+	$(CC) $(CFLAGS) main.c
+
+clean:: EXTRA = 1
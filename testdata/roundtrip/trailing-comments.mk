@@ -0,0 +1,14 @@
+VAR := value # why this value
+OTHER = $(VAR) # derived
+
+ifeq ($(VAR),value) # the default
+target: prereq # build the target
+	echo $(OTHER)
+else # overridden
+target:
+	echo other
+endif # VAR
+
+ifdef OTHER # set above
+other: ; echo inline
+endif
@@ -0,0 +1,3 @@
+FLAGS := $(CC ) ${CFLAGS  }
+target:
+	echo $(CC ) x
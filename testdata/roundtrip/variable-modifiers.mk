@@ -0,0 +1,8 @@
+export GOBIN := $(LOCALBIN)
+override CFLAGS += -g
+private export  SECRET = hunter2
+
+export PATH GOBIN
+unexport
+override undefine CFLAGS
+undefine SECRET
@@ -0,0 +1,4 @@
+vpath %.c src:lib # comment
+vpath %.h   # comment
+vpath  # comment
+vpath
@@ -0,0 +1,5 @@
+vpath %.c src:lib
+vpath %.h  include ../include
+vpath % $(SRC_DIR)/gen
+vpath %.h
+vpath
@@ -26,19 +26,22 @@ const (
 
 	operator_beg
 	// Operators and delimiters
-	LPAREN  // (
-	LBRACE  // {
-	RPAREN  // )
-	RBRACE  // }
-	DOLLAR  // $
-	COLON   // :
-	SEMI    // ;
-	COMMA   // ,
-	APOS    // '
-	QUOTE   // "
-	PIPE    // |
-	NEWLINE // \n
-	TAB     // \t
+	LPAREN       // (
+	LBRACE       // {
+	RPAREN       // )
+	RBRACE       // }
+	DOLLAR       // $
+	COLON        // :
+	DOUBLE_COLON // ::
+	AND_COLON    // &:
+	SEMI         // ;
+	COMMA        // ,
+	APOS         // '
+	QUOTE        // "
+	PIPE         // |
+	NEWLINE      // \n
+	TAB          // \t
+	CONTINUATION // \ followed by a newline
 
 	RECURSIVE_ASSIGN // =
 	SIMPLE_ASSIGN    // :=
@@ -46,6 +49,7 @@ const (
 	IMMEDIATE_ASSIGN // :::=
 	IFNDEF_ASSIGN    // ?=
 	SHELL_ASSIGN     // !=
+	APPEND_ASSIGN    // +=
 	operator_end
 
 	directive_beg
@@ -95,6 +99,7 @@ const (
 	ABSPATH    // $(abspath names...)
 	ERROR      // $(error text...)
 	WARNING    // $(warning text...)
+	INFO       // $(info text...)
 	SHELL      // $(shell command)
 	ORIGIN     // $(origin variable)
 	FLAVOR     // $(flavor variable)
@@ -117,19 +122,22 @@ var tokens = [...]string{
 	COMMENT: "COMMENT",
 	TEXT:    "TEXT",
 
-	LPAREN:  "(",
-	LBRACE:  "{",
-	RPAREN:  ")",
-	RBRACE:  "}",
-	DOLLAR:  "$",
-	COLON:   ":",
-	SEMI:    ";",
-	COMMA:   ",",
-	APOS:    "'",
-	QUOTE:   `"`,
-	PIPE:    "|",
-	NEWLINE: "\n",
-	TAB:     "\t",
+	LPAREN:       "(",
+	LBRACE:       "{",
+	RPAREN:       ")",
+	RBRACE:       "}",
+	DOLLAR:       "$",
+	COLON:        ":",
+	DOUBLE_COLON: "::",
+	AND_COLON:    "&:",
+	SEMI:         ";",
+	COMMA:        ",",
+	APOS:         "'",
+	QUOTE:        `"`,
+	PIPE:         "|",
+	NEWLINE:      "\n",
+	TAB:          "\t",
+	CONTINUATION: "\\\n",
 
 	RECURSIVE_ASSIGN: "=",
 	SIMPLE_ASSIGN:    ":=",
@@ -137,6 +145,7 @@ var tokens = [...]string{
 	IMMEDIATE_ASSIGN: ":::=",
 	IFNDEF_ASSIGN:    "?=",
 	SHELL_ASSIGN:     "!=",
+	APPEND_ASSIGN:    "+=",
 
 	DEFINE:       "define",
 	ENDEF:        "endef",
@@ -180,6 +189,7 @@ var tokens = [...]string{
 	ABSPATH:    "abspath",
 	ERROR:      "error",
 	WARNING:    "warning",
+	INFO:       "info",
 	SHELL:      "shell",
 	ORIGIN:     "origin",
 	FLAVOR:     "flavor",
@@ -282,8 +292,8 @@ func IsLit(text string) bool {
 		return true
 	}
 	switch text {
-	case "(", ")", "{", "}", "$", ":", ";", ",", "'", `"`, "\n", "\t", "|", "#", " ", "",
-		"=", ":=", "::=", ":::=", "?=", "!=":
+	case "(", ")", "{", "}", "$", ":", "::", "&:", ";", ",", "'", `"`, "\n", "\t", "\\\n", "|", "#", " ", "",
+		"=", ":=", "::=", ":::=", "?=", "!=", "+=":
 		return false
 	}
 
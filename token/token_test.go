@@ -20,17 +20,21 @@ var Operators = []TableEntry{
 	Entry(nil, token.RBRACE),
 	Entry(nil, token.DOLLAR),
 	Entry(nil, token.COLON),
+	Entry(nil, token.DOUBLE_COLON),
+	Entry(nil, token.AND_COLON),
 	Entry(nil, token.COMMA),
 	Entry(nil, token.APOS),
 	Entry(nil, token.QUOTE),
 	Entry(nil, token.NEWLINE),
 	Entry(nil, token.TAB),
+	Entry(nil, token.CONTINUATION),
 	Entry(nil, token.RECURSIVE_ASSIGN),
 	Entry(nil, token.SIMPLE_ASSIGN),
 	Entry(nil, token.POSIX_ASSIGN),
 	Entry(nil, token.IMMEDIATE_ASSIGN),
 	Entry(nil, token.IFNDEF_ASSIGN),
 	Entry(nil, token.SHELL_ASSIGN),
+	Entry(nil, token.APPEND_ASSIGN),
 }
 
 var Directives = []TableEntry{
@@ -78,6 +82,7 @@ var Functions = []TableEntry{
 	Entry(nil, token.ABSPATH),
 	Entry(nil, token.ERROR),
 	Entry(nil, token.WARNING),
+	Entry(nil, token.INFO),
 	Entry(nil, token.SHELL),
 	Entry(nil, token.ORIGIN),
 	Entry(nil, token.FLAVOR),
@@ -158,17 +163,21 @@ var _ = Describe("Token", func() {
 		Entry(nil, token.RBRACE, "}"),
 		Entry(nil, token.DOLLAR, "$"),
 		Entry(nil, token.COLON, ":"),
+		Entry(nil, token.DOUBLE_COLON, "::"),
+		Entry(nil, token.AND_COLON, "&:"),
 		Entry(nil, token.COMMA, ","),
 		Entry(nil, token.APOS, "'"),
 		Entry(nil, token.QUOTE, `"`),
 		Entry(nil, token.NEWLINE, "\n"),
 		Entry(nil, token.TAB, "\t"),
+		Entry(nil, token.CONTINUATION, "\\\n"),
 		Entry(nil, token.RECURSIVE_ASSIGN, "="),
 		Entry(nil, token.SIMPLE_ASSIGN, ":="),
 		Entry(nil, token.POSIX_ASSIGN, "::="),
 		Entry(nil, token.IMMEDIATE_ASSIGN, ":::="),
 		Entry(nil, token.IFNDEF_ASSIGN, "?="),
 		Entry(nil, token.SHELL_ASSIGN, "!="),
+		Entry(nil, token.APPEND_ASSIGN, "+="),
 		Entry(nil, token.DEFINE, "define"),
 		Entry(nil, token.ENDEF, "endef"),
 		Entry(nil, token.UNDEFINE, "undefine"),
@@ -210,6 +219,7 @@ var _ = Describe("Token", func() {
 		Entry(nil, token.ABSPATH, "abspath"),
 		Entry(nil, token.ERROR, "error"),
 		Entry(nil, token.WARNING, "warning"),
+		Entry(nil, token.INFO, "info"),
 		Entry(nil, token.SHELL, "shell"),
 		Entry(nil, token.ORIGIN, "origin"),
 		Entry(nil, token.FLAVOR, "flavor"),
@@ -272,6 +282,7 @@ var _ = Describe("Token", func() {
 			Entry(nil, token.ABSPATH, "abspath"),
 			Entry(nil, token.ERROR, "error"),
 			Entry(nil, token.WARNING, "warning"),
+			Entry(nil, token.INFO, "info"),
 			Entry(nil, token.SHELL, "shell"),
 			Entry(nil, token.ORIGIN, "origin"),
 			Entry(nil, token.FLAVOR, "flavor"),
@@ -342,6 +353,7 @@ var _ = Describe("Token", func() {
 			Entry(nil, "abspath"),
 			Entry(nil, "error"),
 			Entry(nil, "warning"),
+			Entry(nil, "info"),
 			Entry(nil, "shell"),
 			Entry(nil, "origin"),
 			Entry(nil, "flavor"),
@@ -428,6 +440,7 @@ var _ = Describe("Token", func() {
 			Entry(nil, "abspath"),
 			Entry(nil, "error"),
 			Entry(nil, "warning"),
+			Entry(nil, "info"),
 			Entry(nil, "shell"),
 			Entry(nil, "origin"),
 			Entry(nil, "flavor"),
@@ -498,6 +511,7 @@ var _ = Describe("Token", func() {
 			Entry(nil, "abspath"),
 			Entry(nil, "error"),
 			Entry(nil, "warning"),
+			Entry(nil, "info"),
 			Entry(nil, "shell"),
 			Entry(nil, "origin"),
 			Entry(nil, "flavor"),
@@ -522,6 +536,8 @@ var _ = Describe("Token", func() {
 			Entry(nil, "{"),
 			Entry(nil, "}"),
 			Entry(nil, ":"),
+			Entry(nil, "::"),
+			Entry(nil, "&:"),
 			Entry(nil, ";"),
 			Entry(nil, "$"),
 			Entry(nil, "#"),
@@ -533,9 +549,11 @@ var _ = Describe("Token", func() {
 			Entry(nil, "::="),
 			Entry(nil, ":::="),
 			Entry(nil, "\n"),
+			Entry(nil, "\\\n"),
 			Entry(nil, "\t"),
 			Entry(nil, "?="),
 			Entry(nil, "!="),
+			Entry(nil, "+="),
 			Entry(nil, "|"),
 			Entry(nil, " "),
 			Entry(nil, ""),